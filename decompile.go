@@ -0,0 +1,90 @@
+package main
+
+import (
+    "bufio"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/data"
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/provider"
+)
+
+const decompileUsage = `Usage: terraform-provider-logstruct decompile [flags] [pattern ...]
+
+Prints a logstruct_pattern data source block equivalent to each CloudWatch
+Logs filter pattern, or explains the clauses that cannot be expressed.
+Patterns are read one per line from standard input when none are given.
+
+Flags:
+`
+
+// decompile runs the decompile subcommand and returns the exit status: 0 when
+// every pattern was decompiled, 1 when some could not be, 2 on usage errors.
+func decompile(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+    fs := flag.NewFlagSet("decompile", flag.ContinueOnError)
+    fs.SetOutput(stderr)
+    fs.Usage = func() {
+        fmt.Fprint(stderr, decompileUsage)
+        fs.PrintDefaults()
+    }
+    name := fs.String("name", "pattern", "Data source label; several patterns are numbered name_1, name_2, ...")
+    version := fs.String("logstruct-version", "", "LogStruct gem version whose embedded catalog to use (default: latest)")
+    catalogPath := fs.String("catalog", "", "Path to a catalog JSON file replacing the embedded catalog")
+    if err := fs.Parse(args); err != nil { return 2 }
+
+    client, err := decompileClient(*version, *catalogPath)
+    if err != nil {
+        fmt.Fprintln(stderr, "decompile:", err)
+        return 2
+    }
+    patterns := fs.Args()
+    if len(patterns) == 0 {
+        sc := bufio.NewScanner(stdin)
+        for sc.Scan() {
+            if line := strings.TrimSpace(sc.Text()); line != "" { patterns = append(patterns, line) }
+        }
+        if err := sc.Err(); err != nil {
+            fmt.Fprintln(stderr, "decompile:", err)
+            return 2
+        }
+    }
+
+    status := 0
+    for i, pattern := range patterns {
+        label := *name
+        if len(patterns) > 1 { label = fmt.Sprintf("%s_%d", *name, i+1) }
+        hcl, err := client.Decompile(label, pattern)
+        if err != nil {
+            fmt.Fprintf(stderr, "%s: cannot decompile %s:\n", label, pattern)
+            for _, line := range strings.Split(err.Error(), "\n") { fmt.Fprintln(stderr, "  "+line) }
+            status = 1
+            continue
+        }
+        if i > 0 { fmt.Fprintln(stdout) }
+        fmt.Fprint(stdout, hcl)
+    }
+    return status
+}
+
+// decompileClient loads the catalog the way the provider does when configured
+// with logstruct_version and catalog_path.
+func decompileClient(version, catalogPath string) (*provider.MetadataClient, error) {
+    if version == "" { version = data.LatestVersion() }
+    cat, err := data.CatalogForVersion(version)
+    if err != nil { return nil, err }
+    if catalogPath != "" {
+        b, err := os.ReadFile(catalogPath)
+        if err != nil { return nil, err }
+        cat, err = data.ParseCatalog(b)
+        if err != nil { return nil, fmt.Errorf("%s: %v", catalogPath, err) }
+        if cat.Keys == nil || cat.Structs == nil { return nil, fmt.Errorf("%s: a replacement catalog needs both keys and structs", catalogPath) }
+        if errs := cat.Validate(); len(errs) > 0 { return nil, fmt.Errorf("%s: %w", catalogPath, errors.Join(errs...)) }
+        // a replaced catalog has no LogStruct version, as in the provider
+        version = ""
+    }
+    return provider.NewMetadataClientFromCatalog(version, cat), nil
+}
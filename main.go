@@ -4,6 +4,7 @@ import (
     "context"
     "flag"
     "log"
+    "os"
 
     "github.com/hashicorp/terraform-plugin-framework/providerserver"
     "github.com/DocSpring/terraform-provider-logstruct/pkg/provider"
@@ -15,6 +16,10 @@ var (
 )
 
 func main() {
+    if len(os.Args) > 1 && os.Args[1] == "decompile" {
+        os.Exit(decompile(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+    }
+
     var debug bool
     flag.BoolVar(&debug, "debug", false, "Enable debug mode.")
     flag.Parse()
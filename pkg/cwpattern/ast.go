@@ -0,0 +1,168 @@
+// Package cwpattern parses CloudWatch Logs filter patterns into a syntax tree
+// and evaluates them against log events, so that compiled patterns can be
+// checked offline against the log lines they are meant to count.
+//
+// All three forms of the filter syntax are supported: term patterns
+// (ERROR ?WARN -DEBUG "some phrase" %regex%), JSON patterns
+// ({ $.evt = "finish" && ($.duration_ms > 100 || $.error NOT EXISTS) }) and
+// space-delimited patterns ([ip, user, ..., status = 4*, bytes > 1000]).
+package cwpattern
+
+import "regexp"
+
+// Kind is the form of a pattern.
+type Kind int
+
+const (
+    TermPattern      Kind = iota // words, phrases and regular expressions
+    JSONPattern                  // { selector comparisons }
+    DelimitedPattern             // [ space-delimited fields ]
+)
+
+// Pattern is a parsed filter pattern. Terms is set for term patterns, Expr
+// for JSON patterns and Fields for space-delimited patterns.
+type Pattern struct {
+    Kind   Kind
+    Src    string
+    Terms  []Term
+    Expr   Expr
+    Fields []Field
+}
+
+// Node is any element of the tree. Span returns its byte offsets in the
+// pattern source.
+type Node interface {
+    Span() (pos, end int)
+}
+
+// Text returns the source text of n.
+func (p *Pattern) Text(n Node) string {
+    pos, end := n.Span()
+    return p.Src[pos:end]
+}
+
+// TermMode is how a term affects matching.
+type TermMode int
+
+const (
+    Required TermMode = iota // ERROR: the event must contain the term
+    Optional                 // ?ERROR: the event must contain one of the optional terms
+    Excluded                 // -ERROR: the event must not contain the term
+)
+
+// Term is one word, quoted phrase or regular expression of a term pattern.
+type Term struct {
+    Mode     TermMode
+    Value    Value
+    Pos, End int
+}
+
+func (t Term) Span() (int, int) { return t.Pos, t.End }
+
+// ValueKind is the type of a literal.
+type ValueKind int
+
+const (
+    String ValueKind = iota
+    Number
+    Regex
+)
+
+// Value is a literal: a quoted or bare string, which may contain * wildcards,
+// a number, or a regular expression between % signs.
+type Value struct {
+    Kind     ValueKind
+    Text     string // unquoted string, number literal or regular expression
+    Quoted   bool
+    Num      float64
+    Pos, End int
+
+    re *regexp.Regexp
+}
+
+func (v Value) Span() (int, int) { return v.Pos, v.End }
+
+// Expr is a condition of a JSON or space-delimited pattern: a Logical,
+// Comparison or Check.
+type Expr interface {
+    Node
+    isExpr()
+}
+
+// Logical joins two conditions with && or ||.
+type Logical struct {
+    Op       string
+    X, Y     Expr
+    Pos, End int
+}
+
+// Comparison compares the value at a selector with a literal using =, !=,
+// <, <=, > or >=.
+type Comparison struct {
+    Selector Selector
+    Op       string
+    Value    Value
+    Pos, End int
+}
+
+// Check tests a selector with IS NULL, IS TRUE, IS FALSE, EXISTS or
+// NOT EXISTS.
+type Check struct {
+    Selector Selector
+    Op       string
+    Pos, End int
+}
+
+func (e *Logical) Span() (int, int)    { return e.Pos, e.End }
+func (e *Comparison) Span() (int, int) { return e.Pos, e.End }
+func (e *Check) Span() (int, int)      { return e.Pos, e.End }
+func (*Logical) isExpr()               {}
+func (*Comparison) isExpr()            {}
+func (*Check) isExpr()                 {}
+
+// Selector addresses a value: a JSON path such as $.a.b[0] in JSON patterns,
+// or a field name in space-delimited patterns.
+type Selector struct {
+    Text     string
+    Path     []Step
+    Pos, End int
+}
+
+func (s Selector) Span() (int, int) { return s.Pos, s.End }
+
+// Key returns the top-level key the selector addresses, or "" when it starts
+// with an array step.
+func (s Selector) Key() string {
+    if len(s.Path) == 0 || s.Path[0].Array { return "" }
+    return s.Path[0].Key
+}
+
+// Step is one step of a selector path: an object key, or an array index or
+// [*] wildcard when Array is set.
+type Step struct {
+    Key      string
+    Array    bool
+    Index    int
+    Wildcard bool
+}
+
+// Field is one field of a space-delimited pattern: a name with an optional
+// condition, or an ellipsis matching any number of fields.
+type Field struct {
+    Name     string
+    Ellipsis bool
+    Cond     Expr
+    Pos, End int
+}
+
+func (f Field) Span() (int, int) { return f.Pos, f.End }
+
+// Inspect walks e depth-first, calling f for each condition. Children are
+// skipped when f returns false.
+func Inspect(e Expr, f func(Expr) bool) {
+    if e == nil || !f(e) { return }
+    if l, ok := e.(*Logical); ok {
+        Inspect(l.X, f)
+        Inspect(l.Y, f)
+    }
+}
@@ -0,0 +1,232 @@
+package cwpattern
+
+import (
+    "encoding/json"
+    "strconv"
+    "strings"
+)
+
+// Match reports whether the pattern matches a log event. JSON patterns only
+// match events that are JSON objects; a comparison whose selector is missing
+// from the event never matches, whatever its operator.
+func (p *Pattern) Match(event string) bool {
+    switch p.Kind {
+    case JSONPattern:
+        dec := json.NewDecoder(strings.NewReader(event))
+        dec.UseNumber()
+        var doc any
+        if err := dec.Decode(&doc); err != nil || dec.More() { return false }
+        if _, ok := doc.(map[string]any); !ok { return false }
+        return evalJSON(p.Expr, doc)
+    case DelimitedPattern:
+        var conds []Expr
+        for _, f := range p.Fields {
+            if f.Cond != nil { conds = append(conds, f.Cond) }
+        }
+        return matchFields(conds, p.Fields, splitFields(event), map[string]string{})
+    }
+    return matchTerms(p.Terms, event)
+}
+
+// matchTerms requires every required term, at least one optional term when
+// there are any, and no excluded term. Terms match as case-sensitive
+// substrings.
+func matchTerms(terms []Term, event string) bool {
+    optional, matchedOptional := false, false
+    for _, t := range terms {
+        found := t.Value.matchText(event)
+        switch t.Mode {
+        case Required:
+            if !found { return false }
+        case Optional:
+            optional = true
+            if found { matchedOptional = true }
+        case Excluded:
+            if found { return false }
+        }
+    }
+    return !optional || matchedOptional
+}
+
+func (v Value) matchText(event string) bool {
+    if v.Kind == Regex { return v.re.MatchString(event) }
+    return strings.Contains(event, v.Text)
+}
+
+func evalJSON(e Expr, doc any) bool {
+    switch n := e.(type) {
+    case *Logical:
+        if n.Op == "&&" { return evalJSON(n.X, doc) && evalJSON(n.Y, doc) }
+        return evalJSON(n.X, doc) || evalJSON(n.Y, doc)
+    case *Check:
+        values, found := n.Selector.lookup(doc)
+        switch n.Op {
+        case "EXISTS":
+            return found
+        case "NOT EXISTS":
+            return !found
+        }
+        for _, v := range values {
+            switch {
+            case n.Op == "IS NULL" && v == nil, n.Op == "IS TRUE" && v == true, n.Op == "IS FALSE" && v == false:
+                return true
+            }
+        }
+        return false
+    case *Comparison:
+        values, _ := n.Selector.lookup(doc)
+        for _, v := range values {
+            if n.compareJSON(v) { return true }
+        }
+        return false
+    }
+    return false
+}
+
+// lookup returns the values the selector addresses in doc, several when it
+// has a [*] step, and whether any exist.
+func (s Selector) lookup(doc any) ([]any, bool) {
+    values := []any{doc}
+    for _, step := range s.Path {
+        var next []any
+        for _, v := range values {
+            switch {
+            case !step.Array:
+                obj, ok := v.(map[string]any)
+                if !ok { continue }
+                if child, ok := obj[step.Key]; ok { next = append(next, child) }
+            case step.Wildcard:
+                arr, _ := v.([]any)
+                next = append(next, arr...)
+            default:
+                arr, ok := v.([]any)
+                if ok && step.Index < len(arr) { next = append(next, arr[step.Index]) }
+            }
+        }
+        values = next
+    }
+    return values, len(values) > 0
+}
+
+// compareJSON compares a JSON value with the literal. Numbers only compare
+// with numbers and strings with strings.
+func (c *Comparison) compareJSON(v any) bool {
+    if c.Value.Kind == Number {
+        num, ok := v.(json.Number)
+        if !ok { return false }
+        f, err := num.Float64()
+        return err == nil && compareNumbers(f, c.Op, c.Value.Num)
+    }
+    s, ok := v.(string)
+    if !ok { return false }
+    return c.Value.matchString(s) == (c.Op == "=")
+}
+
+// matchString matches s against a string literal, in which * is a wildcard,
+// or a regular expression.
+func (v Value) matchString(s string) bool {
+    if v.Kind == Regex { return v.re.MatchString(s) }
+    return glob(v.Text, s)
+}
+
+func compareNumbers(x float64, op string, y float64) bool {
+    switch op {
+    case "=":
+        return x == y
+    case "!=":
+        return x != y
+    case "<":
+        return x < y
+    case "<=":
+        return x <= y
+    case ">":
+        return x > y
+    case ">=":
+        return x >= y
+    }
+    return false
+}
+
+// glob reports whether s matches pattern, in which * matches any run of
+// characters.
+func glob(pattern, s string) bool {
+    parts := strings.Split(pattern, "*")
+    if len(parts) == 1 { return pattern == s }
+    if !strings.HasPrefix(s, parts[0]) { return false }
+    s = s[len(parts[0]):]
+    last := parts[len(parts)-1]
+    for _, part := range parts[1 : len(parts)-1] {
+        i := strings.Index(s, part)
+        if i < 0 { return false }
+        s = s[i+len(part):]
+    }
+    return strings.HasSuffix(s, last)
+}
+
+// splitFields splits an event on spaces, keeping "quoted" and [bracketed]
+// runs as single fields without their delimiters.
+func splitFields(event string) []string {
+    var fields []string
+    for i := 0; i < len(event); {
+        if isSpace(event[i]) { i++; continue }
+        var closing byte
+        switch event[i] {
+        case '"':
+            closing = '"'
+        case '[':
+            closing = ']'
+        }
+        if closing != 0 {
+            if end := strings.IndexByte(event[i+1:], closing); end >= 0 {
+                fields = append(fields, event[i+1:i+1+end])
+                i += end + 2
+                continue
+            }
+        }
+        start := i
+        for i < len(event) && !isSpace(event[i]) { i++ }
+        fields = append(fields, event[start:i])
+    }
+    return fields
+}
+
+// matchFields binds pattern fields to event fields, letting each ellipsis
+// absorb any number of them, and evaluates conds once every field is bound.
+func matchFields(conds []Expr, pattern []Field, values []string, bound map[string]string) bool {
+    if len(pattern) == 0 {
+        if len(values) > 0 { return false }
+        for _, c := range conds {
+            if !evalFields(c, bound) { return false }
+        }
+        return true
+    }
+    f := pattern[0]
+    if f.Ellipsis {
+        for skip := 0; skip <= len(values); skip++ {
+            if matchFields(conds, pattern[1:], values[skip:], bound) { return true }
+        }
+        return false
+    }
+    if len(values) == 0 { return false }
+    bound[f.Name] = values[0]
+    ok := matchFields(conds, pattern[1:], values[1:], bound)
+    delete(bound, f.Name)
+    return ok
+}
+
+func evalFields(e Expr, bound map[string]string) bool {
+    switch n := e.(type) {
+    case *Logical:
+        if n.Op == "&&" { return evalFields(n.X, bound) && evalFields(n.Y, bound) }
+        return evalFields(n.X, bound) || evalFields(n.Y, bound)
+    case *Comparison:
+        v, ok := bound[n.Selector.Text]
+        if !ok { return false }
+        if n.Value.Kind == Number {
+            f, err := strconv.ParseFloat(v, 64)
+            return err == nil && compareNumbers(f, n.Op, n.Value.Num)
+        }
+        return n.Value.matchString(v) == (n.Op == "=")
+    }
+    return false
+}
@@ -0,0 +1,52 @@
+package cwpattern
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+    cases := []struct {
+        pattern string
+        event   string
+        want    bool
+    }{
+        {`{ $.evt = "finish" && $.src = "job" }`, `{"evt":"finish","src":"job"}`, true},
+        {`{ $.evt = "finish" && $.src = "job" }`, `{"evt":"start","src":"job"}`, false},
+        {`{ $.evt = "finish" || $.evt = "start" }`, `{"evt":"start"}`, true},
+        {`{ $.evt = "fin*" }`, `{"evt":"finish"}`, true},
+        {`{ $.evt = "*ish" }`, `{"evt":"finish"}`, true},
+        {`{ $.evt = %^fin% }`, `{"evt":"finish"}`, true},
+        {`{ $.evt != "finish" }`, `{"evt":"start"}`, true},
+        {`{ $.evt != "finish" }`, `{"src":"job"}`, false}, // missing keys never match
+        {`{ $.duration_ms > 100 }`, `{"duration_ms":150.5}`, true},
+        {`{ $.duration_ms > 100 }`, `{"duration_ms":"150"}`, false},
+        {`{ $.status = 500 }`, `{"status":5e2}`, true},
+        {`{ $.status = "500" }`, `{"status":500}`, false},
+        {`{ $.a.b[1] = "y" }`, `{"a":{"b":["x","y"]}}`, true},
+        {`{ $.tags[*] = "slow" }`, `{"tags":["fast","slow"]}`, true},
+        {`{ $.err NOT EXISTS }`, `{"evt":"finish"}`, true},
+        {`{ $.err EXISTS }`, `{"err":null}`, true},
+        {`{ $.err IS NULL }`, `{"err":null}`, true},
+        {`{ $.err IS NULL }`, `{"evt":"x"}`, false},
+        {`{ $.ok IS TRUE }`, `{"ok":true}`, true},
+        {`{ $.ok IS FALSE }`, `{"ok":true}`, false},
+        {`{ $.evt = "finish" }`, `evt=finish`, false},
+        {`{ $.evt = "finish" }`, `["finish"]`, false},
+        {`ERROR`, `2024-01-01 ERROR boom`, true},
+        {`ERROR -timeout`, `ERROR timeout`, false},
+        {`?WARN ?ERROR`, `INFO ok`, false},
+        {`?WARN ?ERROR`, `WARN disk`, true},
+        {`"disk full"`, `WARN disk full`, true},
+        {`%5\d\d%`, `status 503`, true},
+        {``, `anything`, true},
+        {`[ip, user, ..., status = 4*, bytes]`, `10.0.0.1 bob [10/Oct/2000:13:55:36] "GET / HTTP/1.0" 404 2326`, true},
+        {`[ip, user, ..., status = 4*, bytes]`, `10.0.0.1 bob [10/Oct/2000:13:55:36] "GET / HTTP/1.0" 200 2326`, false},
+        {`[ip, ..., status = 404 || status = 410, bytes > 1000]`, `10.0.0.1 x 410 2326`, true},
+        {`[ip, ..., status = 404 || status = 410, bytes > 1000]`, `10.0.0.1 x 410 99`, false},
+        {`[a, b]`, `one two three`, false},
+        {`[a = one, b != "x y"]`, `one "x z"`, true},
+    }
+    for _, tc := range cases {
+        p, err := Parse(tc.pattern)
+        if err != nil { t.Errorf("%s: %v", tc.pattern, err); continue }
+        if got := p.Match(tc.event); got != tc.want { t.Errorf("%s against %s: got %v, want %v", tc.pattern, tc.event, got, tc.want) }
+    }
+}
@@ -0,0 +1,300 @@
+package cwpattern
+
+import (
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+// numberLiteral matches the numbers the filter syntax accepts.
+var numberLiteral = regexp.MustCompile(`^-?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// Parse parses a filter pattern. Patterns starting with { are JSON patterns,
+// those starting with [ space-delimited patterns, and anything else a term
+// pattern; the empty pattern matches every event.
+func Parse(src string) (*Pattern, error) {
+    trimmed := strings.TrimLeft(src, " \t\r\n")
+    p := &parser{sc: scanner{src: src}}
+    switch {
+    case strings.HasPrefix(trimmed, "{"):
+        e, err := p.json()
+        if err != nil { return nil, err }
+        return &Pattern{Kind: JSONPattern, Src: src, Expr: e}, nil
+    case strings.HasPrefix(trimmed, "["):
+        fields, err := p.delimited()
+        if err != nil { return nil, err }
+        return &Pattern{Kind: DelimitedPattern, Src: src, Fields: fields}, nil
+    }
+    terms, err := parseTerms(src)
+    if err != nil { return nil, err }
+    return &Pattern{Kind: TermPattern, Src: src, Terms: terms}, nil
+}
+
+type parser struct {
+    sc  scanner
+    tok token
+}
+
+func (p *parser) advance() error {
+    t, err := p.sc.next()
+    if err != nil { return err }
+    p.tok = t
+    return nil
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+    t := p.tok
+    if t.kind != kind { return t, p.unexpected(what) }
+    return t, p.advance()
+}
+
+func (p *parser) unexpected(what string) error {
+    if p.tok.kind == tEOF { return &SyntaxError{p.tok.pos, "expected " + what + ", got end of pattern"} }
+    return &SyntaxError{p.tok.pos, fmt.Sprintf("expected %s, got %q", what, p.sc.src[p.tok.pos:p.tok.end])}
+}
+
+// json parses { condition }.
+func (p *parser) json() (Expr, error) {
+    if err := p.advance(); err != nil { return nil, err }
+    if _, err := p.expect(tLBrace, "{"); err != nil { return nil, err }
+    e, err := p.or(p.jsonCondition)
+    if err != nil { return nil, err }
+    if _, err := p.expect(tRBrace, "&&, || or }"); err != nil { return nil, err }
+    if p.tok.kind != tEOF { return nil, p.unexpected("end of pattern") }
+    return e, nil
+}
+
+// delimited parses [ field, field, ... ].
+func (p *parser) delimited() ([]Field, error) {
+    if err := p.advance(); err != nil { return nil, err }
+    if _, err := p.expect(tLBracket, "["); err != nil { return nil, err }
+    var fields []Field
+    for {
+        f, err := p.field()
+        if err != nil { return nil, err }
+        fields = append(fields, f)
+        if p.tok.kind == tComma {
+            if err := p.advance(); err != nil { return nil, err }
+            continue
+        }
+        if _, err := p.expect(tRBracket, ", or ]"); err != nil { return nil, err }
+        break
+    }
+    if p.tok.kind != tEOF { return nil, p.unexpected("end of pattern") }
+    return fields, nil
+}
+
+// field parses one space-delimited field: ..., a name, or a condition whose
+// first selector names the field.
+func (p *parser) field() (Field, error) {
+    t := p.tok
+    if t.kind != tWord { return Field{}, p.unexpected("field name or ...") }
+    if t.text == "..." {
+        return Field{Ellipsis: true, Pos: t.pos, End: t.end}, p.advance()
+    }
+    // look ahead for a condition, then rescan from the name
+    saved := p.sc
+    if err := p.advance(); err != nil { return Field{}, err }
+    if p.tok.kind == tComma || p.tok.kind == tRBracket {
+        return Field{Name: t.text, Pos: t.pos, End: t.end}, nil
+    }
+    p.sc, p.tok = saved, t
+    cond, err := p.or(p.delimitedCondition)
+    if err != nil { return Field{}, err }
+    _, end := cond.Span()
+    return Field{Name: t.text, Cond: cond, Pos: t.pos, End: end}, nil
+}
+
+// or parses operands joined by || and &&, && binding tighter.
+func (p *parser) or(operand func() (Expr, error)) (Expr, error) {
+    x, err := p.and(operand)
+    if err != nil { return nil, err }
+    for p.tok.kind == tOr {
+        if err := p.advance(); err != nil { return nil, err }
+        y, err := p.and(operand)
+        if err != nil { return nil, err }
+        x = logical("||", x, y)
+    }
+    return x, nil
+}
+
+func (p *parser) and(operand func() (Expr, error)) (Expr, error) {
+    x, err := p.group(operand)
+    if err != nil { return nil, err }
+    for p.tok.kind == tAnd {
+        if err := p.advance(); err != nil { return nil, err }
+        y, err := p.group(operand)
+        if err != nil { return nil, err }
+        x = logical("&&", x, y)
+    }
+    return x, nil
+}
+
+func (p *parser) group(operand func() (Expr, error)) (Expr, error) {
+    if p.tok.kind != tLParen { return operand() }
+    if err := p.advance(); err != nil { return nil, err }
+    e, err := p.or(operand)
+    if err != nil { return nil, err }
+    if _, err := p.expect(tRParen, "&&, || or )"); err != nil { return nil, err }
+    return e, nil
+}
+
+func logical(op string, x, y Expr) Expr {
+    pos, _ := x.Span()
+    _, end := y.Span()
+    return &Logical{Op: op, X: x, Y: y, Pos: pos, End: end}
+}
+
+// jsonCondition parses a comparison or check on a JSON selector.
+func (p *parser) jsonCondition() (Expr, error) {
+    t := p.tok
+    if t.kind != tSelector { return nil, p.unexpected("selector such as $.key") }
+    sel, err := parseSelector(t)
+    if err != nil { return nil, err }
+    if err := p.advance(); err != nil { return nil, err }
+
+    if p.tok.kind == tWord {
+        var op string
+        switch p.tok.text {
+        case "IS":
+            if err := p.advance(); err != nil { return nil, err }
+            if p.tok.kind != tWord || p.tok.text != "NULL" && p.tok.text != "TRUE" && p.tok.text != "FALSE" { return nil, p.unexpected("NULL, TRUE or FALSE") }
+            op = "IS " + p.tok.text
+        case "NOT":
+            if err := p.advance(); err != nil { return nil, err }
+            if p.tok.kind != tWord || p.tok.text != "EXISTS" { return nil, p.unexpected("EXISTS") }
+            op = "NOT EXISTS"
+        case "EXISTS":
+            op = "EXISTS"
+        default:
+            return nil, p.unexpected("operator")
+        }
+        end := p.tok.end
+        return &Check{Selector: sel, Op: op, Pos: t.pos, End: end}, p.advance()
+    }
+    return p.comparison(sel)
+}
+
+// delimitedCondition parses a comparison on a field name.
+func (p *parser) delimitedCondition() (Expr, error) {
+    t := p.tok
+    if t.kind != tWord || t.text == "..." { return nil, p.unexpected("field name") }
+    if err := p.advance(); err != nil { return nil, err }
+    return p.comparison(Selector{Text: t.text, Path: []Step{{Key: t.text}}, Pos: t.pos, End: t.end})
+}
+
+func (p *parser) comparison(sel Selector) (Expr, error) {
+    opTok, err := p.expect(tOp, "operator")
+    if err != nil { return nil, err }
+    v, err := p.value()
+    if err != nil { return nil, err }
+    if opTok.text != "=" && opTok.text != "!=" && v.Kind != Number {
+        return nil, &SyntaxError{v.Pos, fmt.Sprintf("operator %s requires a number", opTok.text)}
+    }
+    return &Comparison{Selector: sel, Op: opTok.text, Value: v, Pos: sel.Pos, End: v.End}, nil
+}
+
+func (p *parser) value() (Value, error) {
+    t := p.tok
+    v := Value{Text: t.text, Pos: t.pos, End: t.end}
+    switch t.kind {
+    case tString:
+        v.Quoted = true
+    case tRegex:
+        re, err := compileRegex(t)
+        if err != nil { return v, err }
+        v.Kind, v.re = Regex, re
+    case tWord:
+        if numberLiteral.MatchString(t.text) {
+            n, err := strconv.ParseFloat(t.text, 64)
+            if err != nil { return v, &SyntaxError{t.pos, "invalid number " + t.text} }
+            v.Kind, v.Num = Number, n
+        }
+    default:
+        return v, p.unexpected("value")
+    }
+    return v, p.advance()
+}
+
+func compileRegex(t token) (*regexp.Regexp, error) {
+    if t.text == "" { return nil, &SyntaxError{t.pos, "empty regular expression"} }
+    re, err := regexp.Compile(t.text)
+    if err != nil { return nil, &SyntaxError{t.pos, "invalid regular expression: " + err.Error()} }
+    return re, nil
+}
+
+// parseSelector parses $.key.key[0][*] into steps.
+func parseSelector(t token) (Selector, error) {
+    sel := Selector{Text: t.text, Pos: t.pos, End: t.end}
+    s := t.text[1:]
+    if s == "" { return sel, &SyntaxError{t.pos, "selector must address a key, e.g. $.key"} }
+    for off := 1; s != ""; {
+        switch s[0] {
+        case '.':
+            n := strings.IndexAny(s[1:], ".[")
+            if n < 0 { n = len(s) - 1 }
+            key := s[1 : n+1]
+            if key == "" { return sel, &SyntaxError{t.pos + off, "empty key in selector"} }
+            sel.Path = append(sel.Path, Step{Key: key})
+            s, off = s[n+1:], off+n+1
+        case '[':
+            n := strings.IndexByte(s, ']')
+            step := Step{Array: true}
+            if idx := s[1:n]; idx == "*" {
+                step.Wildcard = true
+            } else {
+                i, err := strconv.Atoi(idx)
+                if err != nil || i < 0 { return sel, &SyntaxError{t.pos + off, fmt.Sprintf("invalid array index %q", idx)} }
+                step.Index = i
+            }
+            sel.Path = append(sel.Path, step)
+            s, off = s[n+1:], off+n+1
+        default:
+            return sel, &SyntaxError{t.pos + off, "expected . or [ in selector"}
+        }
+    }
+    return sel, nil
+}
+
+// parseTerms parses a term pattern: words, "quoted phrases" and %regular
+// expressions%, each optionally prefixed by ? or -.
+func parseTerms(src string) ([]Term, error) {
+    var terms []Term
+    sc := scanner{src: src}
+    for {
+        for sc.pos < len(src) && isSpace(src[sc.pos]) { sc.pos++ }
+        if sc.pos >= len(src) { return terms, nil }
+        term := Term{Pos: sc.pos}
+        switch src[sc.pos] {
+        case '?':
+            term.Mode = Optional
+            sc.pos++
+        case '-':
+            term.Mode = Excluded
+            sc.pos++
+        }
+        start := sc.pos
+        v := Value{Pos: start}
+        switch {
+        case sc.pos >= len(src) || isSpace(src[sc.pos]):
+            return nil, &SyntaxError{term.Pos, "expected a term after " + src[term.Pos:sc.pos]}
+        case src[sc.pos] == '"':
+            text, err := sc.quoted('"')
+            if err != nil { return nil, err }
+            v.Text, v.Quoted = text, true
+        case src[sc.pos] == '%':
+            text, err := sc.quoted('%')
+            if err != nil { return nil, err }
+            re, err := compileRegex(token{text: text, pos: start})
+            if err != nil { return nil, err }
+            v.Text, v.Kind, v.re = text, Regex, re
+        default:
+            for sc.pos < len(src) && !isSpace(src[sc.pos]) { sc.pos++ }
+            v.Text = src[start:sc.pos]
+        }
+        v.End, term.End = sc.pos, sc.pos
+        term.Value = v
+        terms = append(terms, term)
+    }
+}
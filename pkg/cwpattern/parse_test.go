@@ -0,0 +1,74 @@
+package cwpattern
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestParse_JSON(t *testing.T) {
+    p, err := Parse(`{ ($.evt = "finish" || $.evt = "start") && $.items[0].qty >= 2 && $.err NOT EXISTS }`)
+    if err != nil { t.Fatalf("parse: %v", err) }
+    if p.Kind != JSONPattern { t.Fatalf("kind: got %v", p.Kind) }
+
+    var got []string
+    Inspect(p.Expr, func(e Expr) bool {
+        if _, ok := e.(*Logical); !ok { got = append(got, p.Text(e)) }
+        return true
+    })
+    want := []string{`$.evt = "finish"`, `$.evt = "start"`, `$.items[0].qty >= 2`, `$.err NOT EXISTS`}
+    if len(got) != len(want) { t.Fatalf("got %q, want %q", got, want) }
+    for i := range want {
+        if got[i] != want[i] { t.Errorf("condition %d: got %q, want %q", i, got[i], want[i]) }
+    }
+
+    root := p.Expr.(*Logical)
+    if root.Op != "&&" { t.Errorf("&& should bind looser than the parenthesized ||, got %s", root.Op) }
+    cmp := root.X.(*Logical).Y.(*Comparison)
+    if cmp.Value.Kind != Number || cmp.Value.Num != 2 { t.Errorf("value: got %+v", cmp.Value) }
+    if path := cmp.Selector.Path; len(path) != 3 || path[0].Key != "items" || !path[1].Array || path[1].Index != 0 || path[2].Key != "qty" {
+        t.Errorf("path: got %+v", path)
+    }
+    if k := cmp.Selector.Key(); k != "items" { t.Errorf("key: got %s", k) }
+}
+
+func TestParse_Delimited(t *testing.T) {
+    p, err := Parse(`[ip, user, ..., status = 4* || status = 5*, bytes > 1000]`)
+    if err != nil { t.Fatalf("parse: %v", err) }
+    if p.Kind != DelimitedPattern || len(p.Fields) != 5 { t.Fatalf("got %+v", p) }
+    if !p.Fields[2].Ellipsis || p.Fields[3].Name != "status" || p.Fields[4].Name != "bytes" { t.Errorf("fields: got %+v", p.Fields) }
+    if got := p.Text(p.Fields[3]); got != "status = 4* || status = 5*" { t.Errorf("field text: got %q", got) }
+}
+
+func TestParse_Terms(t *testing.T) {
+    p, err := Parse(`ERROR ?"timed out" ?%5\d\d% -DEBUG`)
+    if err != nil { t.Fatalf("parse: %v", err) }
+    if p.Kind != TermPattern || len(p.Terms) != 4 { t.Fatalf("got %+v", p) }
+    modes := []TermMode{Required, Optional, Optional, Excluded}
+    for i, m := range modes {
+        if p.Terms[i].Mode != m { t.Errorf("term %d: mode %v, want %v", i, p.Terms[i].Mode, m) }
+    }
+    if v := p.Terms[1].Value; v.Text != "timed out" || !v.Quoted { t.Errorf("phrase: got %+v", v) }
+    if v := p.Terms[2].Value; v.Kind != Regex || v.Text != `5\d\d` { t.Errorf("regex: got %+v", v) }
+}
+
+func TestParse_Errors(t *testing.T) {
+    cases := map[string]int{
+        `{ $.evt = "finish"`:            18, // missing }
+        `{ $.evt = "finish }`:           10,
+        `{ $.evt == "finish" }`:         8,
+        `{ $.duration_ms > "slow" }`:    18,
+        `{ $.evt = "a" & $.src = "b" }`: 14,
+        `{ evt = "a" }`:                 2,
+        `{ $.a[x] = 1 }`:                5,
+        `{ $.err IS MISSING }`:          11,
+        `{ $.a = %(% }`:                 8,
+        `[a, b`:                         5,
+        `ERROR ?`:                       6,
+    }
+    for src, offset := range cases {
+        _, err := Parse(src)
+        var syntax *SyntaxError
+        if !errors.As(err, &syntax) { t.Errorf("%s: expected syntax error, got %v", src, err); continue }
+        if syntax.Offset != offset { t.Errorf("%s: error at %d, want %d (%v)", src, syntax.Offset, offset, err) }
+    }
+}
@@ -0,0 +1,129 @@
+package cwpattern
+
+import (
+    "fmt"
+    "strings"
+)
+
+// SyntaxError is a pattern that cannot be parsed. Offset is the byte offset
+// of the problem in the pattern.
+type SyntaxError struct {
+    Offset int
+    Msg    string
+}
+
+func (e *SyntaxError) Error() string { return fmt.Sprintf("column %d: %s", e.Offset+1, e.Msg) }
+
+type tokenKind int
+
+const (
+    tEOF tokenKind = iota
+    tLBrace
+    tRBrace
+    tLBracket
+    tRBracket
+    tLParen
+    tRParen
+    tComma
+    tAnd
+    tOr
+    tOp       // =, !=, <, <=, >, >=
+    tSelector // $.a.b[0]
+    tString   // "..."
+    tRegex    // %...%
+    tWord     // bare words, numbers, keywords and ...
+)
+
+type token struct {
+    kind     tokenKind
+    text     string // unquoted contents for strings and regular expressions
+    pos, end int
+}
+
+var punctuation = map[byte]tokenKind{'{': tLBrace, '}': tRBrace, '[': tLBracket, ']': tRBracket, '(': tLParen, ')': tRParen, ',': tComma}
+
+// special are the bytes that end a bare word or selector.
+const special = "{}[](),=!<>&|\"%"
+
+// scanner splits the condition syntax shared by JSON and space-delimited
+// patterns into tokens.
+type scanner struct {
+    src string
+    pos int
+}
+
+func (s *scanner) next() (token, error) {
+    for s.pos < len(s.src) && isSpace(s.src[s.pos]) { s.pos++ }
+    start := s.pos
+    if s.pos >= len(s.src) { return token{kind: tEOF, pos: start, end: start}, nil }
+
+    c := s.src[s.pos]
+    if k, ok := punctuation[c]; ok {
+        s.pos++
+        return token{kind: k, text: string(c), pos: start, end: s.pos}, nil
+    }
+    switch c {
+    case '&', '|':
+        if s.pos+1 < len(s.src) && s.src[s.pos+1] == c {
+            s.pos += 2
+            if c == '&' { return token{kind: tAnd, text: "&&", pos: start, end: s.pos}, nil }
+            return token{kind: tOr, text: "||", pos: start, end: s.pos}, nil
+        }
+        return token{}, &SyntaxError{start, fmt.Sprintf("expected %c%c", c, c)}
+    case '=', '!', '<', '>':
+        s.pos++
+        if s.pos < len(s.src) && s.src[s.pos] == '=' { s.pos++ }
+        op := s.src[start:s.pos]
+        if op == "!" { return token{}, &SyntaxError{start, "expected !="} }
+        // == is not CloudWatch syntax
+        if op == "==" { return token{}, &SyntaxError{start, "use = rather than =="} }
+        return token{kind: tOp, text: op, pos: start, end: s.pos}, nil
+    case '"':
+        text, err := s.quoted('"')
+        return token{kind: tString, text: text, pos: start, end: s.pos}, err
+    case '%':
+        text, err := s.quoted('%')
+        return token{kind: tRegex, text: text, pos: start, end: s.pos}, err
+    }
+
+    kind := tWord
+    if c == '$' { kind = tSelector }
+    for s.pos < len(s.src) && !isSpace(s.src[s.pos]) {
+        c := s.src[s.pos]
+        // selectors keep their [index] steps
+        if kind == tSelector && c == '[' {
+            end := strings.IndexByte(s.src[s.pos:], ']')
+            if end < 0 { return token{}, &SyntaxError{s.pos, "unterminated [ in selector"} }
+            s.pos += end + 1
+            continue
+        }
+        if strings.IndexByte(special, c) >= 0 { break }
+        s.pos++
+    }
+    return token{kind: kind, text: s.src[start:s.pos], pos: start, end: s.pos}, nil
+}
+
+// quoted scans a string delimited by q, in which \q and \\ are escapes, and
+// returns its contents.
+func (s *scanner) quoted(q byte) (string, error) {
+    start := s.pos
+    var b strings.Builder
+    for s.pos++; s.pos < len(s.src); s.pos++ {
+        c := s.src[s.pos]
+        if c == '\\' && s.pos+1 < len(s.src) && (s.src[s.pos+1] == q || s.src[s.pos+1] == '\\') {
+            // regular expressions keep \\ for the regexp package
+            if q == '%' && s.src[s.pos+1] == '\\' { b.WriteByte('\\') }
+            s.pos++
+            b.WriteByte(s.src[s.pos])
+            continue
+        }
+        if c == q {
+            s.pos++
+            return b.String(), nil
+        }
+        b.WriteByte(c)
+    }
+    return "", &SyntaxError{start, fmt.Sprintf("unterminated %c", q)}
+}
+
+func isSpace(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' }
@@ -0,0 +1,251 @@
+package data
+
+import (
+    "bytes"
+    "embed"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "io/fs"
+    "path"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// Field kinds: the JSON type a field's value serializes to, normalized from
+// its LogStruct type.
+const (
+    KindString    = "string"
+    KindInteger   = "integer"
+    KindFloat     = "float"
+    KindBoolean   = "boolean"
+    KindArray     = "array"
+    KindObject    = "object"
+    KindTimestamp = "timestamp"
+    KindAny       = "any" // untyped; may serialize to any JSON type
+)
+
+// Kinds lists the field kinds.
+var Kinds = []string{KindString, KindInteger, KindFloat, KindBoolean, KindArray, KindObject, KindTimestamp, KindAny}
+
+// Field is a key a struct emits.
+type Field struct {
+    Name     string `json:"name"`
+    Key      string `json:"key"`
+    Type     string `json:"type"` // type as declared in LogStruct, e.g. T::Array[String]
+    Kind     string `json:"kind"`
+    Optional bool   `json:"optional"`
+}
+
+// Numeric reports whether the field's values are numbers.
+func (f Field) Numeric() bool { return IsNumericKind(f.Kind) }
+
+// IsNumericKind reports whether kind is integer or float.
+func IsNumericKind(kind string) bool { return kind == KindInteger || kind == KindFloat }
+
+type StructCatalog struct {
+    Name          string   `json:"name"`
+    FixedSource   *string  `json:"fixed_source"`
+    AllowedEvents []string `json:"allowed_events"`
+    Fields        []Field  `json:"fields"`
+}
+
+type Catalog struct {
+    Keys    map[string]string        `json:"keys"`
+    Structs map[string]StructCatalog `json:"structs"`
+}
+
+// embeddedCatalogs holds one catalog per released LogStruct version, named
+// <version>.json. Catalogs are exported by scripts/export_provider_catalog.rb
+// in the LogStruct gem repository (github.com/DocSpring/logstruct), not by
+// anything in this repository.
+//
+//go:embed catalogs/*.json
+var embeddedCatalogs embed.FS
+
+// catalogFiles is where catalogs/<version>.json are read from; tests swap in
+// fixtures.
+var catalogFiles fs.FS = embeddedCatalogs
+
+// CatalogData is the catalog of the latest embedded LogStruct version.
+var CatalogData = mustCatalog(LatestVersion())
+
+// Versions returns the embedded LogStruct versions, oldest first.
+func Versions() []string {
+    entries, err := fs.ReadDir(catalogFiles, "catalogs")
+    if err != nil { panic(err) }
+    var versions []string
+    for _, e := range entries { versions = append(versions, strings.TrimSuffix(e.Name(), ".json")) }
+    sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) < 0 })
+    return versions
+}
+
+// LatestVersion returns the newest embedded LogStruct version.
+func LatestVersion() string {
+    versions := Versions()
+    return versions[len(versions)-1]
+}
+
+// CatalogForVersion returns the embedded catalog for a LogStruct version.
+func CatalogForVersion(version string) (Catalog, error) {
+    b, err := fs.ReadFile(catalogFiles, path.Join("catalogs", version+".json"))
+    if err != nil {
+        return Catalog{}, fmt.Errorf("no catalog embedded for LogStruct %s; available versions: %s", version, strings.Join(Versions(), ", "))
+    }
+    cat, err := ParseCatalog(b)
+    if err != nil { return Catalog{}, fmt.Errorf("catalogs/%s.json: %v", version, err) }
+    return cat, nil
+}
+
+func mustCatalog(version string) Catalog {
+    cat, err := CatalogForVersion(version)
+    if err != nil { panic(err) }
+    return cat
+}
+
+// compareVersions orders dotted version strings numerically, segment by
+// segment, falling back to string order for non-numeric segments.
+func compareVersions(a, b string) int {
+    as, bs := strings.Split(a, "."), strings.Split(b, ".")
+    for i := 0; i < len(as) || i < len(bs); i++ {
+        var x, y string
+        if i < len(as) { x = as[i] }
+        if i < len(bs) { y = bs[i] }
+        xn, xerr := strconv.Atoi(x)
+        yn, yerr := strconv.Atoi(y)
+        switch {
+        case xerr == nil && yerr == nil && xn != yn:
+            if xn < yn { return -1 }
+            return 1
+        case (xerr != nil || yerr != nil) && x != y:
+            return strings.Compare(x, y)
+        }
+    }
+    return 0
+}
+
+// ParseCatalog decodes a catalog in the schema of the embedded catalogs. Unknown
+// fields are rejected so that typos surface instead of being ignored, and
+// syntax errors report the line and column they occur at.
+func ParseCatalog(b []byte) (Catalog, error) {
+    var cat Catalog
+    dec := json.NewDecoder(bytes.NewReader(b))
+    dec.DisallowUnknownFields()
+    if err := dec.Decode(&cat); err != nil {
+        var syntax *json.SyntaxError
+        var typ *json.UnmarshalTypeError
+        switch {
+        case errors.Is(err, io.EOF):
+            return cat, fmt.Errorf("catalog is empty")
+        case errors.As(err, &syntax):
+            // Offset points just past the offending byte
+            line, col := position(b, syntax.Offset-1)
+            return cat, fmt.Errorf("line %d, column %d: %v", line, col, err)
+        case errors.As(err, &typ):
+            line, col := position(b, typ.Offset)
+            return cat, fmt.Errorf("line %d, column %d: %s must be %s, got %s", line, col, typ.Field, typ.Type, typ.Value)
+        }
+        return cat, err
+    }
+    if dec.More() { return cat, fmt.Errorf("unexpected data after the catalog object") }
+    return cat, nil
+}
+
+// Merge returns a copy of c with other's keys and structs laid over it. Structs
+// are replaced whole, by name.
+func (c Catalog) Merge(other Catalog) Catalog {
+    out := Catalog{Keys: map[string]string{}, Structs: map[string]StructCatalog{}}
+    for k, v := range c.Keys { out.Keys[k] = v }
+    for k, v := range other.Keys { out.Keys[k] = v }
+    for k, v := range c.Structs { out.Structs[k] = v }
+    for k, v := range other.Structs { out.Structs[k] = v }
+    return out
+}
+
+// RequiredKeys are the canonical keys every catalog must map: the selectors
+// match on event and source, and every LogStruct log carries level and
+// timestamp.
+var RequiredKeys = []string{"event", "source", "level", "timestamp"}
+
+// Validate checks the catalog's integrity and returns every problem found,
+// keys first and then structs by name: missing required keys, canonical keys
+// sharing a serialized key, empty names and values, structs without events,
+// struct entries whose name differs from their key, and fields of unknown
+// kind or serialized differently from keys.
+func (c Catalog) Validate() []error {
+    var errs []error
+    add := func(format string, args ...any) { errs = append(errs, fmt.Errorf(format, args...)) }
+
+    for _, k := range RequiredKeys {
+        if _, ok := c.Keys[k]; !ok { add("keys: missing required key %q", k) }
+    }
+    names := make([]string, 0, len(c.Keys))
+    for name := range c.Keys { names = append(names, name) }
+    sort.Strings(names)
+    owner := map[string]string{}
+    for _, name := range names {
+        key := c.Keys[name]
+        switch {
+        case name == "":
+            add("keys: empty canonical key name")
+        case key == "":
+            add("keys.%s: empty serialized key", name)
+        case owner[key] != "":
+            add("keys.%s: serializes to %q, like keys.%s", name, key, owner[key])
+        default:
+            owner[key] = name
+        }
+    }
+
+    structs := make([]string, 0, len(c.Structs))
+    for name := range c.Structs { structs = append(structs, name) }
+    sort.Strings(structs)
+    for _, name := range structs {
+        sc := c.Structs[name]
+        at := "structs." + name
+        switch {
+        case name == "":
+            add("structs: empty struct name")
+        case sc.Name != name:
+            add("%s: name is %q; it must equal the struct's key", at, sc.Name)
+        }
+        if sc.FixedSource != nil && *sc.FixedSource == "" { add("%s: empty fixed_source; omit it for structs without a fixed source", at) }
+        if len(sc.AllowedEvents) == 0 { add("%s: no allowed_events", at) }
+        seen := map[string]bool{}
+        for _, ev := range sc.AllowedEvents {
+            switch {
+            case ev == "":
+                add("%s: empty event in allowed_events", at)
+            case seen[ev]:
+                add("%s: duplicate event %q in allowed_events", at, ev)
+            }
+            seen[ev] = true
+        }
+        fieldFor := map[string]string{}
+        for i, f := range sc.Fields {
+            fat := fmt.Sprintf("%s.fields[%d]", at, i)
+            if f.Name == "" || f.Key == "" { add("%s: empty name or key", fat); continue }
+            fat = at + ".fields." + f.Name
+            if key, ok := c.Keys[f.Name]; ok && key != f.Key { add("%s: serializes to %q, but keys.%s is %q", fat, f.Key, f.Name, key) }
+            if other, ok := fieldFor[f.Key]; ok { add("%s: serializes to %q, like field %s", fat, f.Key, other) }
+            fieldFor[f.Key] = f.Name
+            known := f.Kind == ""
+            for _, k := range Kinds { if f.Kind == k { known = true } }
+            if !known { add("%s: unknown kind %q; expected one of %s", fat, f.Kind, strings.Join(Kinds, ", ")) }
+        }
+    }
+    return errs
+}
+
+// position converts a byte offset into a 1-based line and column.
+func position(b []byte, offset int64) (int, int) {
+    if offset < 0 { offset = 0 }
+    if offset > int64(len(b)) { offset = int64(len(b)) }
+    line, col := 1, 1
+    for _, c := range b[:offset] {
+        if c == '\n' { line++; col = 1 } else { col++ }
+    }
+    return line, col
+}
@@ -0,0 +1,134 @@
+package data
+
+import (
+    "io/fs"
+    "os"
+    "strings"
+    "testing"
+    "testing/fstest"
+)
+
+func TestCatalogForVersion_Embedded(t *testing.T) {
+    for _, v := range Versions() {
+        cat, err := CatalogForVersion(v)
+        if err != nil { t.Fatalf("%s: %v", v, err) }
+        if len(cat.Keys) == 0 || len(cat.Structs) == 0 { t.Errorf("%s: empty catalog", v) }
+        for _, err := range cat.Validate() { t.Errorf("%s: %v", v, err) }
+        for name, sc := range cat.Structs {
+            for _, f := range sc.Fields {
+                known := false
+                for _, k := range Kinds { if f.Kind == k { known = true } }
+                if !known { t.Errorf("%s: %s.%s has kind %q", v, name, f.Name, f.Kind) }
+            }
+        }
+    }
+    if got := *CatalogData.Structs["ActionMailer"].FixedSource; got != "mailer" { t.Errorf("fixed source: got %s", got) }
+    if _, err := CatalogForVersion("0.0.0-nope"); err == nil || !strings.Contains(err.Error(), LatestVersion()) {
+        t.Errorf("expected error listing available versions, got %v", err)
+    }
+}
+
+// withFixtureCatalogs serves the embedded catalogs plus the fixture catalogs
+// in testdata/catalogs for the duration of the test.
+func withFixtureCatalogs(t *testing.T) {
+    t.Helper()
+    files := fstest.MapFS{}
+    for _, dir := range []fs.FS{embeddedCatalogs, os.DirFS("testdata")} {
+        names, err := fs.Glob(dir, "catalogs/*.json")
+        if err != nil { t.Fatalf("glob: %v", err) }
+        for _, name := range names {
+            b, err := fs.ReadFile(dir, name)
+            if err != nil { t.Fatalf("read %s: %v", name, err) }
+            files[name] = &fstest.MapFile{Data: b}
+        }
+    }
+    saved := catalogFiles
+    catalogFiles = files
+    t.Cleanup(func() { catalogFiles = saved })
+}
+
+func TestCatalogForVersion_Selection(t *testing.T) {
+    withFixtureCatalogs(t)
+    if got := strings.Join(Versions(), ","); got != "0.1.0,0.10.0" { t.Errorf("versions: got %s", got) }
+    if got := LatestVersion(); got != "0.10.0" { t.Errorf("latest: got %s", got) }
+
+    old, err := CatalogForVersion("0.1.0")
+    if err != nil { t.Fatalf("0.1.0: %v", err) }
+    latest, err := CatalogForVersion("0.10.0")
+    if err != nil { t.Fatalf("0.10.0: %v", err) }
+    for _, err := range latest.Validate() { t.Errorf("0.10.0: %v", err) }
+    if _, ok := old.Structs["ActionCable"]; ok { t.Errorf("0.1.0 should not have ActionCable") }
+    if _, ok := latest.Structs["ActionCable"]; !ok { t.Errorf("0.10.0 should have ActionCable") }
+    if _, ok := old.Structs["Dotenv"]; !ok { t.Errorf("0.1.0 should have Dotenv") }
+    if _, ok := latest.Structs["Dotenv"]; ok { t.Errorf("0.10.0 should not have Dotenv") }
+}
+
+func TestCompareVersions(t *testing.T) {
+    ordered := []string{"0.0.9", "0.1.0", "0.1.2", "0.2.0", "0.10.0", "1.0.0"}
+    for i := 0; i+1 < len(ordered); i++ {
+        if compareVersions(ordered[i], ordered[i+1]) >= 0 { t.Errorf("expected %s < %s", ordered[i], ordered[i+1]) }
+        if compareVersions(ordered[i+1], ordered[i]) <= 0 { t.Errorf("expected %s > %s", ordered[i+1], ordered[i]) }
+    }
+    if compareVersions("0.1.0", "0.1.0") != 0 { t.Errorf("expected equal versions") }
+}
+
+func TestParseCatalog_Errors(t *testing.T) {
+    cases := map[string]string{
+        "":                                  "empty",
+        "{\n  \"keys\": {\"a\": \"b\",}\n}":  "line 2, column 21",
+        `{"keys": {"a": 1}}`:                "keys.a must be string",
+        `{"keyz": {}}`:                      "unknown field",
+        `{"keys": {}} {}`:                   "unexpected data",
+    }
+    for in, want := range cases {
+        _, err := ParseCatalog([]byte(in))
+        if err == nil || !strings.Contains(err.Error(), want) { t.Errorf("%q: got %v, want error containing %q", in, err, want) }
+    }
+}
+
+func TestCatalog_Merge(t *testing.T) {
+    src := "custom"
+    merged := CatalogData.Merge(Catalog{
+        Keys:    map[string]string{"tenant": "tnt"},
+        Structs: map[string]StructCatalog{"Custom": {Name: "Custom", FixedSource: &src, AllowedEvents: []string{"log"}}},
+    })
+    if merged.Keys["tenant"] != "tnt" || merged.Keys["event"] != "evt" { t.Errorf("keys not merged") }
+    if _, ok := merged.Structs["Custom"]; !ok { t.Errorf("struct not merged") }
+    if _, ok := merged.Structs["ActionMailer"]; !ok { t.Errorf("embedded struct lost") }
+    if _, ok := CatalogData.Keys["tenant"]; ok { t.Errorf("merge modified the receiver") }
+}
+
+func TestCatalog_Validate(t *testing.T) {
+    src, empty := "custom", ""
+    cat := Catalog{
+        Keys: map[string]string{"event": "evt", "source": "src", "level": "evt", "tenant": ""},
+        Structs: map[string]StructCatalog{
+            "Custom": {Name: "Custom", FixedSource: &src, AllowedEvents: []string{"log", "log", ""}, Fields: []Field{
+                {Name: "event", Key: "event", Kind: KindString},
+                {Name: "count", Key: "n", Kind: "number"},
+                {Name: "total", Key: "n", Kind: KindInteger},
+                {Name: "", Key: "x"},
+            }},
+            "Other": {Name: "Renamed", FixedSource: &empty},
+        },
+    }
+    want := []string{
+        `keys: missing required key "timestamp"`,
+        `keys.level: serializes to "evt", like keys.event`,
+        `keys.tenant: empty serialized key`,
+        `structs.Custom: duplicate event "log" in allowed_events`,
+        `structs.Custom: empty event in allowed_events`,
+        `structs.Custom.fields.event: serializes to "event", but keys.event is "evt"`,
+        `structs.Custom.fields.count: unknown kind "number"`,
+        `structs.Custom.fields.total: serializes to "n", like field count`,
+        `structs.Custom.fields[3]: empty name or key`,
+        `structs.Other: name is "Renamed"; it must equal the struct's key`,
+        `structs.Other: empty fixed_source`,
+        `structs.Other: no allowed_events`,
+    }
+    errs := cat.Validate()
+    if len(errs) != len(want) { t.Fatalf("got %d problems, want %d: %v", len(errs), len(want), errs) }
+    for i, w := range want {
+        if !strings.Contains(errs[i].Error(), w) { t.Errorf("problem %d: got %q, want %q", i, errs[i], w) }
+    }
+}
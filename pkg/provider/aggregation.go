@@ -0,0 +1,78 @@
+package provider
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/data"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// aggregateFunctions are the stats functions every query dialect supports,
+// under their canonical names.
+var aggregateFunctions = []string{"avg", "count", "count_distinct", "max", "min", "stddev", "sum"}
+
+// numericAggregates are the functions that only apply to numeric fields.
+var numericAggregates = []string{"avg", "stddev", "sum"}
+
+// aggregation is a validated stats block: a function over a serialized key,
+// grouped by serialized keys.
+type aggregation struct {
+    Function string
+    Field    string // empty counts every record
+    Kind     string // kind of the field's values, if known
+    By       []string
+}
+
+type statsModel struct {
+    Function types.String   `tfsdk:"function"`
+    Field    types.String   `tfsdk:"field"`
+    By       []types.String `tfsdk:"by"`
+}
+
+// statsAttributes returns the attributes of a stats block, with any extra
+// dialect-specific ones added.
+func statsAttributes(extra map[string]schema.Attribute) map[string]schema.Attribute {
+    attrs := map[string]schema.Attribute{
+        "function": schema.StringAttribute{Optional: true, Description: "One of " + strings.Join(aggregateFunctions, ", ") + " (default count)"},
+        "field":    schema.StringAttribute{Optional: true, Description: "Canonical key name to aggregate; required except for count"},
+        "by":       schema.ListAttribute{Optional: true, ElementType: types.StringType, Description: "Canonical key names to group by"},
+    }
+    for k, v := range extra { attrs[k] = v }
+    return attrs
+}
+
+// aggregation validates a stats block at path at. keyFor maps a field name to
+// the dialect's field reference, normally SerializedKey.
+func (c *MetadataClient) aggregation(m statsModel, at path.Path, keyFor func(string) (string, error)) (aggregation, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    agg := aggregation{Function: m.Function.ValueString()}
+    if agg.Function == "" { agg.Function = "count" }
+    if !contains(aggregateFunctions, agg.Function) {
+        diags.AddAttributeError(at.AtName("function"), "Invalid stats function", fmt.Sprintf("function must be one of %s, got %s", strings.Join(aggregateFunctions, ", "), agg.Function))
+        return agg, diags
+    }
+
+    if !m.Field.IsNull() {
+        key, err := keyFor(m.Field.ValueString())
+        if err != nil { diags.AddAttributeError(at.AtName("field"), "Unknown field", err.Error()); return agg, diags }
+        agg.Field, agg.Kind = key, c.KeyKind(m.Field.ValueString())
+        if kind := agg.Kind; contains(numericAggregates, agg.Function) && kind != "" && kind != data.KindAny && !data.IsNumericKind(kind) {
+            diags.AddAttributeError(at.AtName("field"), "Invalid stats field", fmt.Sprintf("%s requires a numeric field, but %s holds %s values", agg.Function, m.Field.ValueString(), kind))
+            return agg, diags
+        }
+    } else if agg.Function != "count" {
+        diags.AddAttributeError(at.AtName("field"), "Missing field", agg.Function+" requires a field")
+        return agg, diags
+    }
+
+    for i, b := range m.By {
+        key, err := keyFor(b.ValueString())
+        if err != nil { diags.AddAttributeError(at.AtName("by").AtListIndex(i), "Unknown field", err.Error()); continue }
+        agg.By = append(agg.By, key)
+    }
+    return agg, diags
+}
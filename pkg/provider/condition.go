@@ -0,0 +1,92 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// conditionDepth is how deeply all, any and not groups nest. Terraform
+// schemas cannot be recursive, so the innermost groups hold predicates only.
+const conditionDepth = 3
+
+// conditionGroups are the group blocks of a condition, with their descriptions.
+var conditionGroups = map[string]string{
+    "all": "Group matching when all of its clauses match",
+    "any": "Group matching when at least one of its clauses matches",
+    "not": "Group matching when its clauses, ANDed, do not match. The negation is pushed down to each comparison, and like every comparison those never match logs that lack the key",
+}
+
+// conditionBlocks returns the predicates block and the all, any and not
+// groups, each nesting the same blocks depth levels further.
+func conditionBlocks(depth int) map[string]schema.Block {
+    blocks := map[string]schema.Block{"predicates": predicatesBlock()}
+    if depth == 0 { return blocks }
+    for name, desc := range conditionGroups {
+        blocks[name] = schema.ListNestedBlock{
+            Description:  desc,
+            NestedObject: schema.NestedBlockObject{Blocks: conditionBlocks(depth - 1)},
+        }
+    }
+    return blocks
+}
+
+// conditionClauses compiles predicates and groups (keyed by block name) into
+// the clauses of a condition, reporting errors against paths under at.
+func (c *MetadataClient) conditionClauses(ctx context.Context, preds []predicateModel, groups map[string]types.List, at path.Path) ([]expr, diag.Diagnostics) {
+    clauses, diags := c.predicateExprs(preds, at.AtName("predicates"))
+    for _, name := range []string{"all", "any", "not"} {
+        list, ok := groups[name]
+        if !ok || list.IsNull() || list.IsUnknown() { continue }
+        for i, elem := range list.Elements() {
+            obj, ok := elem.(types.Object)
+            if !ok || obj.IsNull() || obj.IsUnknown() { continue }
+            e, d := c.conditionGroup(ctx, name, obj.Attributes(), at.AtName(name).AtListIndex(i))
+            diags.Append(d...)
+            if e != nil { clauses = append(clauses, e) }
+        }
+    }
+    return clauses, diags
+}
+
+// conditionGroup compiles one all, any or not block.
+func (c *MetadataClient) conditionGroup(ctx context.Context, kind string, attrs map[string]attr.Value, at path.Path) (expr, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    var preds []predicateModel
+    if list, ok := attrs["predicates"].(types.List); ok && !list.IsNull() && !list.IsUnknown() {
+        diags.Append(list.ElementsAs(ctx, &preds, false)...)
+        if diags.HasError() { return nil, diags }
+    }
+    groups := map[string]types.List{}
+    for name := range conditionGroups {
+        if list, ok := attrs[name].(types.List); ok { groups[name] = list }
+    }
+    clauses, d := c.conditionClauses(ctx, preds, groups, at)
+    diags.Append(d...)
+    if diags.HasError() { return nil, diags }
+    if len(clauses) == 0 {
+        diags.AddAttributeError(at, "Empty group", kind+" must contain at least one predicate or group")
+        return nil, diags
+    }
+
+    switch kind {
+    case "any":
+        if len(clauses) == 1 { return clauses[0], diags }
+        return anyExpr(clauses), diags
+    case "not":
+        return negate(conjunction(clauses)), diags
+    }
+    return conjunction(clauses), diags
+}
+
+// conjunction ANDs clauses, flattening nested conjunctions.
+func conjunction(clauses []expr) expr {
+    if len(clauses) == 1 { return clauses[0] }
+    var out allExpr
+    for _, e := range clauses { out = out.and(e) }
+    return out
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+    "context"
+    "testing"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/cwpattern"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNegate(t *testing.T) {
+    e := allExpr{
+        cmpExpr{Key: "evt", Op: opEqual, Value: "finish"},
+        anyExpr{cmpExpr{Key: "queue_name", Op: opNotEqual, Value: "low"}, cmpExpr{Key: "duration_ms", Op: opGreater, Value: "5", Numeric: true}},
+    }
+    want := `{ $.evt != "finish" || ($.queue_name = "low" && $.duration_ms <= 5) }`
+    if got := renderCloudWatch(negate(e)); got != want { t.Errorf("got %s, want %s", got, want) }
+    if got := renderCloudWatch(negate(negate(e))); got != renderCloudWatch(e) { t.Errorf("double negation changed %s", got) }
+
+    // a negated comparison never matches an event without the key, like the
+    // comparison itself
+    p, err := cwpattern.Parse(renderCloudWatch(negate(cmpExpr{Key: "duration_ms", Op: opGreater, Value: "5", Numeric: true})))
+    if err != nil { t.Fatalf("parse: %v", err) }
+    for event, want := range map[string]bool{`{"duration_ms": 3}`: true, `{"duration_ms": 10}`: false, `{"evt": "finish"}`: false} {
+        if got := p.Match(event); got != want { t.Errorf("%s against %s: got %v, want %v", p.Src, event, got, want) }
+    }
+}
+
+// leafGroup is the innermost group of conditionBlocks, holding predicates only.
+type leafGroup struct {
+    Predicates []predicateModel `tfsdk:"predicates"`
+}
+
+func pred(key, op string, values ...string) predicateModel {
+    m := predicateModel{Key: types.StringValue(key), Operator: types.StringValue(op), Value: types.StringNull()}
+    for _, v := range values { m.Values = append(m.Values, types.StringValue(v)) }
+    return m
+}
+
+func TestConditionClauses(t *testing.T) {
+    ctx := context.Background()
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+    elem := conditionBlocks(1)["any"].Type().(types.ListType).ElemType
+    group := func(groups ...leafGroup) types.List {
+        l, diags := types.ListValueFrom(ctx, elem, groups)
+        if diags.HasError() { t.Fatalf("list: %v", diags) }
+        return l
+    }
+
+    clauses, diags := c.conditionClauses(ctx, []predicateModel{pred("http_method", "=", "GET")}, map[string]types.List{
+        "any": group(leafGroup{Predicates: []predicateModel{pred("queue_name", "=", "default"), pred("duration_ms", ">", "100")}}),
+        "not": group(leafGroup{Predicates: []predicateModel{pred("queue_name", "=", "low", "bulk")}}),
+    }, path.Empty())
+    if diags.HasError() { t.Fatalf("clauses: %v", diags) }
+    want := `{ $.method = "GET" && ($.queue_name = "default" || $.duration_ms > 100) && $.queue_name != "low" && $.queue_name != "bulk" }`
+    if got := renderCloudWatch(conjunction(clauses)); got != want { t.Errorf("got %s, want %s", got, want) }
+
+    _, diags = c.conditionClauses(ctx, nil, map[string]types.List{
+        "all": group(leafGroup{}, leafGroup{Predicates: []predicateModel{pred("nope", "=", "x")}}),
+    }, path.Empty())
+    if len(diags.Errors()) != 2 { t.Fatalf("expected 2 errors, got %v", diags) }
+    if p := diags.Errors()[0].(diag.DiagnosticWithPath).Path(); !p.Equal(path.Root("all").AtListIndex(0)) { t.Errorf("empty group reported at %s", p) }
+}
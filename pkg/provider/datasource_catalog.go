@@ -0,0 +1,64 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/data"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type catalogDataSource struct{ client *MetadataClient }
+
+func NewCatalogDataSource() datasource.DataSource { return &catalogDataSource{} }
+
+type catalogModel struct {
+    Version           types.String   `tfsdk:"version"`
+    LatestVersion     types.String   `tfsdk:"latest_version"`
+    AvailableVersions []types.String `tfsdk:"available_versions"`
+    Custom            types.Bool     `tfsdk:"custom"`
+}
+
+func (d *catalogDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_catalog"
+}
+
+func (d *catalogDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: map[string]schema.Attribute{
+            "version": schema.StringAttribute{Computed: true, Description: "LogStruct version of the catalog the provider is configured with; null when catalog_path or catalog_json replaces the embedded catalog"},
+            "latest_version": schema.StringAttribute{Computed: true, Description: "Newest LogStruct version with an embedded catalog"},
+            "available_versions": schema.ListAttribute{Computed: true, ElementType: types.StringType, Description: "LogStruct versions with an embedded catalog, oldest first"},
+            "custom": schema.BoolAttribute{Computed: true, Description: "Whether catalog_path or catalog_json replaced or extended the embedded catalog"},
+        },
+    }
+}
+
+func (d *catalogDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *catalogDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    latest := data.LatestVersion()
+    var versions []types.String
+    for _, v := range data.Versions() { versions = append(versions, types.StringValue(v)) }
+
+    version := types.StringNull()
+    if client.Version != "" { version = types.StringValue(client.Version) }
+    data := catalogModel{
+        Version:           version,
+        LatestVersion:     types.StringValue(latest),
+        AvailableVersions: versions,
+        Custom:            types.BoolValue(client.Custom),
+    }
+
+    diags := resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type cloudwatchFilterDataSource struct{ client *MetadataClient }
+
+func NewCloudWatchFilterDataSource() datasource.DataSource { return &cloudwatchFilterDataSource{} }
+
+type cloudwatchFilterModel struct {
+    Struct     types.String     `tfsdk:"struct"`
+    Event      types.String     `tfsdk:"event"`
+    Predicates []predicateModel `tfsdk:"predicates"`
+    Pattern    types.String     `tfsdk:"pattern"`
+}
+
+func (d *cloudwatchFilterDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_cloudwatch_filter"
+}
+
+func (d *cloudwatchFilterDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: map[string]schema.Attribute{
+            "struct":  schema.StringAttribute{Required: true, Description: "LogStruct struct name e.g. ActionMailer"},
+            "event":   schema.StringAttribute{Required: true, Description: "Serialized event value for the struct (e.g., delivered, finish, database)"},
+            "pattern": schema.StringAttribute{Computed: true, Description: "Compiled CloudWatch Logs filter pattern"},
+        },
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+        },
+    }
+}
+
+func (d *cloudwatchFilterDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *cloudwatchFilterDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data cloudwatchFilterModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    sname := data.Struct.ValueString()
+    ev := data.Event.ValueString()
+    if sname == "" || ev == "" {
+        resp.Diagnostics.AddError("Invalid input", "struct and event are required")
+        return
+    }
+
+    if _, ok := client.Structs[sname]; !ok {
+        resp.Diagnostics.AddAttributeError(path.Root("struct"), "Unknown struct", "No struct named "+sname+" in the catalog")
+        return
+    }
+    parts, err := client.structSelector(sname, ev)
+    if err != nil { resp.Diagnostics.AddError("Invalid event", err.Error()); return }
+    preds, diags := client.predicateExprs(data.Predicates, path.Root("predicates"))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+    for _, p := range preds { parts = parts.and(p) }
+    data.Pattern = types.StringValue(renderCloudWatch(parts))
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type datadogQueryDataSource struct{ client *MetadataClient }
+
+func NewDatadogQueryDataSource() datasource.DataSource { return &datadogQueryDataSource{} }
+
+type datadogQueryModel struct {
+    Source        types.String     `tfsdk:"source"`
+    Struct        types.String     `tfsdk:"struct"`
+    Event         types.String     `tfsdk:"event"`
+    Events        []types.String   `tfsdk:"events"`
+    ExcludeEvents []types.String   `tfsdk:"exclude_events"`
+    Predicates    []predicateModel `tfsdk:"predicates"`
+    Query         types.String     `tfsdk:"query"`
+}
+
+func (d *datadogQueryDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_datadog_query"
+}
+
+func (d *datadogQueryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: selectorAttributes(map[string]schema.Attribute{
+            "query": schema.StringAttribute{Computed: true, Description: "Compiled Datadog log search query"},
+        }),
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+        },
+    }
+}
+
+func (d *datadogQueryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *datadogQueryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data datadogQueryModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, data.Predicates))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+    data.Struct = resolved
+    data.Query = types.StringValue(renderDatadog(sel.Expr))
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
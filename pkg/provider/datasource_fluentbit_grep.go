@@ -0,0 +1,100 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type fluentBitGrepDataSource struct{ client *MetadataClient }
+
+func NewFluentBitGrepDataSource() datasource.DataSource { return &fluentBitGrepDataSource{} }
+
+type fluentBitGrepModel struct {
+    Source        types.String         `tfsdk:"source"`
+    Struct        types.String         `tfsdk:"struct"`
+    Event         types.String         `tfsdk:"event"`
+    Events        []types.String       `tfsdk:"events"`
+    ExcludeEvents []types.String       `tfsdk:"exclude_events"`
+    Predicates    []predicateModel     `tfsdk:"predicates"`
+    Match         types.String         `tfsdk:"match"`
+    RecordKey     types.String         `tfsdk:"record_key"`
+    Rules         []fluentBitRuleModel `tfsdk:"rules"`
+    Config        types.String         `tfsdk:"config"`
+}
+
+type fluentBitRuleModel struct {
+    Type types.String `tfsdk:"type"`
+    Rule types.String `tfsdk:"rule"`
+}
+
+func (d *fluentBitGrepDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_fluentbit_grep"
+}
+
+func (d *fluentBitGrepDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: selectorAttributes(map[string]schema.Attribute{
+            "match":      schema.StringAttribute{Optional: true, Description: "Tag pattern the filter applies to (default *)"},
+            "record_key": schema.StringAttribute{Optional: true, Description: "Record key holding the parsed LogStruct map, when it is not merged into the record root"},
+            "rules": schema.ListNestedAttribute{
+                Computed:    true,
+                Description: "Grep rules, for YAML configuration: type is Regex or Exclude, rule is the key and pattern",
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "type": schema.StringAttribute{Computed: true},
+                        "rule": schema.StringAttribute{Computed: true},
+                    },
+                },
+            },
+            "config": schema.StringAttribute{Computed: true, Description: "Compiled [FILTER] section in the classic configuration format"},
+        }),
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+        },
+    }
+}
+
+func (d *fluentBitGrepDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *fluentBitGrepDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data fluentBitGrepModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, data.Predicates))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    g := fluentBitGrep{Match: "*", RecordKey: data.RecordKey.ValueString()}
+    if !data.Match.IsNull() { g.Match = data.Match.ValueString() }
+    if g.Match == "" {
+        resp.Diagnostics.AddAttributeError(path.Root("match"), "Invalid match", "match cannot be empty")
+        return
+    }
+    rules, err := g.rules(sel.Expr)
+    if err != nil { resp.Diagnostics.AddAttributeError(path.Root("predicates"), "Unsupported condition", err.Error()); return }
+    g.Rules = rules
+
+    data.Struct = resolved
+    data.Rules = []fluentBitRuleModel{}
+    for _, r := range rules {
+        data.Rules = append(data.Rules, fluentBitRuleModel{Type: types.StringValue(r.Type), Rule: types.StringValue(r.String())})
+    }
+    data.Config = types.StringValue(g.String())
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
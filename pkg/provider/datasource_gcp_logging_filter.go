@@ -0,0 +1,95 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type gcpLoggingFilterDataSource struct{ client *MetadataClient }
+
+func NewGCPLoggingFilterDataSource() datasource.DataSource { return &gcpLoggingFilterDataSource{} }
+
+type gcpLoggingFilterModel struct {
+    Source        types.String     `tfsdk:"source"`
+    Struct        types.String     `tfsdk:"struct"`
+    Event         types.String     `tfsdk:"event"`
+    Events        []types.String   `tfsdk:"events"`
+    ExcludeEvents []types.String   `tfsdk:"exclude_events"`
+    Predicates    []predicateModel `tfsdk:"predicates"`
+    PayloadField  types.String     `tfsdk:"payload_field"`
+    MinLevel      types.String     `tfsdk:"min_level"`
+    MapSeverity   types.Bool       `tfsdk:"map_level_to_severity"`
+    Filter        types.String     `tfsdk:"filter"`
+}
+
+func (d *gcpLoggingFilterDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_gcp_logging_filter"
+}
+
+func (d *gcpLoggingFilterDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: selectorAttributes(map[string]schema.Attribute{
+            "payload_field":         schema.StringAttribute{Optional: true, Description: "Log entry field holding the LogStruct JSON (default jsonPayload)"},
+            "min_level":             schema.StringAttribute{Optional: true, Description: "Lowest LogStruct level to match (debug, info, warn, error, fatal), rendered as a severity comparison; requires map_level_to_severity"},
+            "map_level_to_severity": schema.BoolAttribute{Optional: true, Description: "Render level predicates against the entry severity instead of the payload; the logging agent must set severity from the LogStruct level (default false)"},
+            "filter":                schema.StringAttribute{Computed: true, Description: "Compiled Cloud Logging filter"},
+        }),
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+        },
+    }
+}
+
+func (d *gcpLoggingFilterDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *gcpLoggingFilterDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data gcpLoggingFilterModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, data.Predicates))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    g := gcpFilter{Payload: "jsonPayload"}
+    if !data.PayloadField.IsNull() { g.Payload = data.PayloadField.ValueString() }
+    if g.Payload == "" {
+        resp.Diagnostics.AddAttributeError(path.Root("payload_field"), "Invalid payload field", "payload_field cannot be empty")
+        return
+    }
+    if data.MapSeverity.ValueBool() {
+        levelKey, err := client.SerializedKey("level")
+        if err != nil { resp.Diagnostics.AddError("Missing key", "'level' key missing from catalog"); return }
+        g.LevelKey = levelKey
+    }
+    if !data.MinLevel.IsNull() {
+        // the payload level is a string, which has no order to compare by
+        if g.LevelKey == "" {
+            resp.Diagnostics.AddAttributeError(path.Root("min_level"), "Conflicting attributes", "min_level is rendered as a severity comparison, so it requires map_level_to_severity = true; without severity mapping, add a level predicate with the levels to match instead")
+            return
+        }
+        sev, err := gcpSeverity(data.MinLevel.ValueString())
+        if err != nil { resp.Diagnostics.AddAttributeError(path.Root("min_level"), "Invalid level", err.Error()); return }
+        g.MinSeverity = sev
+    }
+    out, err := g.render(sel.Expr)
+    if err != nil { resp.Diagnostics.AddAttributeError(path.Root("predicates"), "Invalid level", err.Error()); return }
+    data.Struct = resolved
+    data.Filter = types.StringValue(out)
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
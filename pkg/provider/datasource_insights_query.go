@@ -0,0 +1,151 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "regexp"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type insightsQueryDataSource struct{ client *MetadataClient }
+
+func NewInsightsQueryDataSource() datasource.DataSource { return &insightsQueryDataSource{} }
+
+type insightsQueryModel struct {
+    Source        types.String        `tfsdk:"source"`
+    Struct        types.String        `tfsdk:"struct"`
+    Event         types.String        `tfsdk:"event"`
+    Events        []types.String      `tfsdk:"events"`
+    ExcludeEvents []types.String      `tfsdk:"exclude_events"`
+    Predicates    []predicateModel    `tfsdk:"predicates"`
+    Fields        []types.String      `tfsdk:"fields"`
+    Stats         *insightsStatsModel `tfsdk:"stats"`
+    SortBy        types.String        `tfsdk:"sort_by"`
+    SortOrder     types.String        `tfsdk:"sort_order"`
+    Limit         types.Int64         `tfsdk:"limit"`
+    Query         types.String        `tfsdk:"query"`
+}
+
+type insightsStatsModel struct {
+    Function types.String   `tfsdk:"function"`
+    Field    types.String   `tfsdk:"field"`
+    By       []types.String `tfsdk:"by"`
+    Bin      types.String   `tfsdk:"bin"`
+}
+
+func (d *insightsQueryDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_insights_query"
+}
+
+func (d *insightsQueryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: selectorAttributes(map[string]schema.Attribute{
+            "fields":     schema.ListAttribute{Optional: true, ElementType: types.StringType, Description: "Fields to display: canonical key names, or Insights system fields such as @timestamp"},
+            "sort_by":    schema.StringAttribute{Optional: true, Description: "Canonical key name or system field to sort by"},
+            "sort_order": schema.StringAttribute{Optional: true, Description: "asc or desc (default desc)"},
+            "limit":      schema.Int64Attribute{Optional: true, Description: "Maximum number of results"},
+            "query":      schema.StringAttribute{Computed: true, Description: "Compiled CloudWatch Logs Insights query"},
+        }),
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+            "stats": schema.SingleNestedBlock{
+                Description: "Aggregation rendered as a stats command",
+                Attributes: statsAttributes(map[string]schema.Attribute{
+                    "bin": schema.StringAttribute{Optional: true, Description: "Time bucket to group by: a whole number and a unit of ms, s, m, h, d, w, mo, q or y, e.g. 5m"},
+                }),
+            },
+        },
+    }
+}
+
+func (d *insightsQueryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *insightsQueryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data insightsQueryModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, data.Predicates))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    q := insightsQuery{Filter: sel.Expr, Limit: data.Limit.ValueInt64()}
+    for i, f := range data.Fields {
+        key, err := client.insightsField(f.ValueString())
+        if err != nil { resp.Diagnostics.AddAttributeError(path.Root("fields").AtListIndex(i), "Unknown field", err.Error()); continue }
+        q.Fields = append(q.Fields, key)
+    }
+    if data.Stats != nil {
+        stats, diags := client.insightsStats(*data.Stats)
+        resp.Diagnostics.Append(diags...)
+        q.Stats = stats
+    }
+    if !data.SortBy.IsNull() {
+        key, err := client.insightsField(data.SortBy.ValueString())
+        if err != nil { resp.Diagnostics.AddAttributeError(path.Root("sort_by"), "Unknown field", err.Error()) }
+        q.SortBy = key
+        q.SortOrder = "desc"
+        if !data.SortOrder.IsNull() { q.SortOrder = data.SortOrder.ValueString() }
+        if q.SortOrder != "asc" && q.SortOrder != "desc" {
+            resp.Diagnostics.AddAttributeError(path.Root("sort_order"), "Invalid sort order", "sort_order must be asc or desc, got "+q.SortOrder)
+        }
+    } else if !data.SortOrder.IsNull() {
+        resp.Diagnostics.AddAttributeError(path.Root("sort_order"), "Missing sort_by", "sort_order requires sort_by")
+    }
+    if !data.Limit.IsNull() && (q.Limit < 1 || q.Limit > 10000) {
+        resp.Diagnostics.AddAttributeError(path.Root("limit"), "Invalid limit", fmt.Sprintf("limit must be between 1 and 10000, got %d", q.Limit))
+    }
+    if resp.Diagnostics.HasError() { return }
+
+    data.Struct = resolved
+    data.Query = types.StringValue(q.String())
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
+
+// insightsField maps a canonical key name to its serialized key, passing
+// Insights system fields such as @timestamp through unchanged.
+func (c *MetadataClient) insightsField(name string) (string, error) {
+    if strings.HasPrefix(name, "@") { return name, nil }
+    return c.SerializedKey(name)
+}
+
+// insightsBin matches the time periods bin() accepts, e.g. 5m or 1h.
+var insightsBin = regexp.MustCompile(`^\d+(ms|s|m|h|d|w|mo|q|y)$`)
+
+// insightsStats renders the stats block as the argument of a stats command.
+func (c *MetadataClient) insightsStats(m insightsStatsModel) (string, diag.Diagnostics) {
+    agg, diags := c.aggregation(statsModel{Function: m.Function, Field: m.Field, By: m.By}, path.Root("stats"), c.insightsField)
+    if diags.HasError() { return "", diags }
+
+    arg := agg.Field
+    if arg == "" { arg = "*" }
+    out := fmt.Sprintf("%s(%s)", agg.Function, arg)
+    groups := agg.By
+    if !m.Bin.IsNull() {
+        if !insightsBin.MatchString(m.Bin.ValueString()) {
+            diags.AddAttributeError(path.Root("stats").AtName("bin"), "Invalid bin",
+                fmt.Sprintf("bin must be a number followed by one of ms, s, m, h, d, w, mo, q or y (e.g. 5m), got %q", m.Bin.ValueString()))
+            return "", diags
+        }
+        groups = append(groups, "bin("+m.Bin.ValueString()+")")
+    }
+    if len(groups) > 0 { out += " by " + strings.Join(groups, ", ") }
+    return out, diags
+}
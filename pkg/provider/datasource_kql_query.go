@@ -0,0 +1,99 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type kqlQueryDataSource struct{ client *MetadataClient }
+
+func NewKQLQueryDataSource() datasource.DataSource { return &kqlQueryDataSource{} }
+
+type kqlQueryModel struct {
+    Source        types.String     `tfsdk:"source"`
+    Struct        types.String     `tfsdk:"struct"`
+    Event         types.String     `tfsdk:"event"`
+    Events        []types.String   `tfsdk:"events"`
+    ExcludeEvents []types.String   `tfsdk:"exclude_events"`
+    Predicates    []predicateModel `tfsdk:"predicates"`
+    Table         types.String     `tfsdk:"table"`
+    MessageColumn types.String     `tfsdk:"message_column"`
+    Columns       types.Bool       `tfsdk:"columns"`
+    Stats         *statsModel      `tfsdk:"stats"`
+    Query         types.String     `tfsdk:"query"`
+}
+
+func (d *kqlQueryDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_kql_query"
+}
+
+func (d *kqlQueryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: selectorAttributes(map[string]schema.Attribute{
+            "table":          schema.StringAttribute{Required: true, Description: "Log Analytics table to query (e.g., ContainerLogV2, AppTraces, or a custom _CL table)"},
+            "message_column": schema.StringAttribute{Optional: true, Description: "Column holding the LogStruct JSON, parsed with parse_json (default Message)"},
+            "columns":        schema.BoolAttribute{Optional: true, Description: "Read serialized keys as table columns, for custom tables that store each key in its own column (default false)"},
+            "query":          schema.StringAttribute{Computed: true, Description: "Compiled KQL query"},
+        }),
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+            "stats": schema.SingleNestedBlock{
+                Description: "Aggregation rendered as a summarize operator",
+                Attributes:  statsAttributes(nil),
+            },
+        },
+    }
+}
+
+func (d *kqlQueryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *kqlQueryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data kqlQueryModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, data.Predicates))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    q := kqlQuery{Table: data.Table.ValueString(), MessageColumn: "Message", Filter: sel.Expr}
+    if q.Table == "" {
+        resp.Diagnostics.AddAttributeError(path.Root("table"), "Invalid table", "table cannot be empty")
+        return
+    }
+    if !data.MessageColumn.IsNull() { q.MessageColumn = data.MessageColumn.ValueString() }
+    if data.Columns.ValueBool() {
+        if !data.MessageColumn.IsNull() {
+            resp.Diagnostics.AddAttributeError(path.Root("message_column"), "Conflicting attributes", "message_column cannot be used with columns = true")
+            return
+        }
+        q.MessageColumn = ""
+    } else if q.MessageColumn == "" {
+        resp.Diagnostics.AddAttributeError(path.Root("message_column"), "Invalid message column", "message_column cannot be empty")
+        return
+    }
+    if data.Stats != nil {
+        agg, diags := client.aggregation(*data.Stats, path.Root("stats"), client.SerializedKey)
+        resp.Diagnostics.Append(diags...)
+        if resp.Diagnostics.HasError() { return }
+        q.Stats = &agg
+    }
+    data.Struct = resolved
+    data.Query = types.StringValue(q.String())
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
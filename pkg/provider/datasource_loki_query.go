@@ -0,0 +1,83 @@
+package provider
+
+import (
+    "context"
+    "sort"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type lokiQueryDataSource struct{ client *MetadataClient }
+
+func NewLokiQueryDataSource() datasource.DataSource { return &lokiQueryDataSource{} }
+
+type lokiQueryModel struct {
+    Source         types.String            `tfsdk:"source"`
+    Struct         types.String            `tfsdk:"struct"`
+    Event          types.String            `tfsdk:"event"`
+    Events         []types.String          `tfsdk:"events"`
+    ExcludeEvents  []types.String          `tfsdk:"exclude_events"`
+    Predicates     []predicateModel        `tfsdk:"predicates"`
+    StreamSelector map[string]types.String `tfsdk:"stream_selector"`
+    Query          types.String            `tfsdk:"query"`
+}
+
+func (d *lokiQueryDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_loki_query"
+}
+
+func (d *lokiQueryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: selectorAttributes(map[string]schema.Attribute{
+            "stream_selector": schema.MapAttribute{Required: true, ElementType: types.StringType, Description: "Stream labels to select, e.g. { app = \"web\" }"},
+            "query":           schema.StringAttribute{Computed: true, Description: "Compiled LogQL log query"},
+        }),
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+        },
+    }
+}
+
+func (d *lokiQueryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *lokiQueryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data lokiQueryModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    if len(data.StreamSelector) == 0 {
+        resp.Diagnostics.AddAttributeError(path.Root("stream_selector"), "Invalid stream selector", "at least one stream label is required")
+        return
+    }
+    streams := make(map[string]string, len(data.StreamSelector))
+    var names []string
+    for name, v := range data.StreamSelector { names = append(names, name); streams[name] = v.ValueString() }
+    sort.Strings(names)
+    for _, name := range names {
+        if !isLokiLabelName(name) {
+            resp.Diagnostics.AddAttributeError(path.Root("stream_selector").AtMapKey(name), "Invalid label name", "label names must match [a-zA-Z_][a-zA-Z0-9_]*, got "+name)
+        }
+    }
+    if resp.Diagnostics.HasError() { return }
+
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, data.Predicates))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+    data.Struct = resolved
+    data.Query = types.StringValue(renderLoki(streams, sel.Expr))
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
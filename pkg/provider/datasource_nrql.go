@@ -0,0 +1,85 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type nrqlDataSource struct{ client *MetadataClient }
+
+func NewNRQLDataSource() datasource.DataSource { return &nrqlDataSource{} }
+
+type nrqlModel struct {
+    Source        types.String     `tfsdk:"source"`
+    Struct        types.String     `tfsdk:"struct"`
+    Event         types.String     `tfsdk:"event"`
+    Events        []types.String   `tfsdk:"events"`
+    ExcludeEvents []types.String   `tfsdk:"exclude_events"`
+    Predicates    []predicateModel `tfsdk:"predicates"`
+    From          types.String     `tfsdk:"from"`
+    Stats         *statsModel      `tfsdk:"stats"`
+    Query         types.String     `tfsdk:"query"`
+}
+
+func (d *nrqlDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_nrql"
+}
+
+func (d *nrqlDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: selectorAttributes(map[string]schema.Attribute{
+            "from":  schema.StringAttribute{Optional: true, Description: "Event type to query (default Log)"},
+            "query": schema.StringAttribute{Computed: true, Description: "Compiled NRQL query"},
+        }),
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+            "stats": schema.SingleNestedBlock{
+                Description: "Aggregation to select (default count(*)); by keys are rendered as FACET",
+                Attributes:  statsAttributes(nil),
+            },
+        },
+    }
+}
+
+func (d *nrqlDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *nrqlDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data nrqlModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, data.Predicates))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    q := nrqlQuery{From: "Log", Filter: sel.Expr, Stats: aggregation{Function: "count"}}
+    if !data.From.IsNull() { q.From = data.From.ValueString() }
+    if q.From == "" {
+        resp.Diagnostics.AddAttributeError(path.Root("from"), "Invalid event type", "from cannot be empty")
+        return
+    }
+    if data.Stats != nil {
+        agg, diags := client.aggregation(*data.Stats, path.Root("stats"), client.SerializedKey)
+        resp.Diagnostics.Append(diags...)
+        if resp.Diagnostics.HasError() { return }
+        q.Stats = agg
+    }
+    data.Struct = resolved
+    data.Query = types.StringValue(q.String())
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type opensearchQueryDataSource struct{ client *MetadataClient }
+
+func NewOpenSearchQueryDataSource() datasource.DataSource { return &opensearchQueryDataSource{} }
+
+type opensearchQueryModel struct {
+    Source        types.String            `tfsdk:"source"`
+    Struct        types.String            `tfsdk:"struct"`
+    Event         types.String            `tfsdk:"event"`
+    Events        []types.String          `tfsdk:"events"`
+    ExcludeEvents []types.String          `tfsdk:"exclude_events"`
+    Predicates    []predicateModel        `tfsdk:"predicates"`
+    FieldPrefix   types.String            `tfsdk:"field_prefix"`
+    KeywordSuffix types.String            `tfsdk:"keyword_suffix"`
+    Monitor       *opensearchMonitorModel `tfsdk:"monitor"`
+    Query         types.String            `tfsdk:"query"`
+    MonitorQuery  types.String            `tfsdk:"monitor_query"`
+}
+
+type opensearchMonitorModel struct {
+    Period         types.String `tfsdk:"period"`
+    TimestampField types.String `tfsdk:"timestamp_field"`
+}
+
+func (d *opensearchQueryDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_opensearch_query"
+}
+
+func (d *opensearchQueryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: selectorAttributes(map[string]schema.Attribute{
+            "field_prefix":   schema.StringAttribute{Optional: true, Description: "Prefix for document fields, e.g. log. when LogStruct JSON is nested under log"},
+            "keyword_suffix": schema.StringAttribute{Optional: true, Description: "Suffix for exact string matches, e.g. .keyword for dynamically mapped text fields"},
+            "query":          schema.StringAttribute{Computed: true, Description: "Compiled query DSL clause as JSON"},
+            "monitor_query":  schema.StringAttribute{Computed: true, Description: "Alerting monitor search request as JSON; null without a monitor block"},
+        }),
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+            "monitor": schema.SingleNestedBlock{
+                Description: "Also render a search request for an alerting monitor",
+                Attributes: map[string]schema.Attribute{
+                    "period":          schema.StringAttribute{Optional: true, Description: "Date math period before each run to search, e.g. 1h (default 1h)"},
+                    "timestamp_field": schema.StringAttribute{Optional: true, Description: "Document timestamp field (default @timestamp)"},
+                },
+            },
+        },
+    }
+}
+
+func (d *opensearchQueryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *opensearchQueryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data opensearchQueryModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, data.Predicates))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    fields := opensearchFields{Prefix: data.FieldPrefix.ValueString(), KeywordSuffix: data.KeywordSuffix.ValueString()}
+    q, err := json.Marshal(opensearchQuery(fields, sel.Expr))
+    if err != nil { resp.Diagnostics.AddError("Render error", err.Error()); return }
+    data.Query = types.StringValue(string(q))
+
+    data.MonitorQuery = types.StringNull()
+    if data.Monitor != nil {
+        period, tsField := "1h", "@timestamp"
+        if !data.Monitor.Period.IsNull() { period = data.Monitor.Period.ValueString() }
+        if !data.Monitor.TimestampField.IsNull() { tsField = data.Monitor.TimestampField.ValueString() }
+        if period == "" { resp.Diagnostics.AddAttributeError(path.Root("monitor").AtName("period"), "Invalid period", "period cannot be empty") }
+        if tsField == "" { resp.Diagnostics.AddAttributeError(path.Root("monitor").AtName("timestamp_field"), "Invalid timestamp field", "timestamp_field cannot be empty") }
+        if resp.Diagnostics.HasError() { return }
+        m, err := json.Marshal(opensearchMonitorQuery(fields, sel.Expr, tsField, period))
+        if err != nil { resp.Diagnostics.AddError("Render error", err.Error()); return }
+        data.MonitorQuery = types.StringValue(string(m))
+    }
+    data.Struct = resolved
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
@@ -2,9 +2,6 @@ package provider
 
 import (
     "context"
-    "fmt"
-    "sort"
-    "strings"
 
     "github.com/hashicorp/terraform-plugin-framework/datasource"
     "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -16,9 +13,13 @@ type patternDataSource struct{ client *MetadataClient }
 func NewPatternDataSource() datasource.DataSource { return &patternDataSource{} }
 
 type patternModel struct {
-    Source  types.String `tfsdk:"source"`
-    Event   types.String `tfsdk:"event"`
-    Pattern types.String `tfsdk:"pattern"`
+    Source        types.String     `tfsdk:"source"`
+    Event         types.String     `tfsdk:"event"`
+    Events        []types.String   `tfsdk:"events"`
+    ExcludeEvents []types.String   `tfsdk:"exclude_events"`
+    Struct        types.String     `tfsdk:"struct"`
+    Predicates    []predicateModel `tfsdk:"predicates"`
+    Pattern       types.String     `tfsdk:"pattern"`
 }
 
 func (d *patternDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -29,9 +30,15 @@ func (d *patternDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
     resp.Schema = schema.Schema{
         Attributes: map[string]schema.Attribute{
             "source": schema.StringAttribute{Required: true, Description: "Canonical source value (e.g., mailer, job, rails, storage)"},
-            "event":  schema.StringAttribute{Required: true, Description: "Serialized event value for the struct (e.g., delivered, finish, database)"},
+            "event":  schema.StringAttribute{Optional: true, Description: "Serialized event value for the struct (e.g., delivered, finish, database)"},
+            "events": schema.SetAttribute{Optional: true, ElementType: types.StringType, Description: "Several serialized event values, matched with OR; alternative to event"},
+            "exclude_events": schema.SetAttribute{Optional: true, ElementType: types.StringType, Description: "Events to exclude when neither event nor events is set"},
+            "struct": schema.StringAttribute{Optional: true, Computed: true, Description: "LogStruct struct to match when several share the source (e.g., ActiveJob or GoodJob for job); set to the resolved struct"},
             "pattern": schema.StringAttribute{Computed: true, Description: "Compiled CloudWatch Logs filter pattern"},
         },
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+        },
     }
 }
 
@@ -51,61 +58,15 @@ func (d *patternDataSource) Read(ctx context.Context, req datasource.ReadRequest
         resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
         return
     }
-    src := data.Source.ValueString()
-    ev := data.Event.ValueString()
-    if src == "" || ev == "" {
-        resp.Diagnostics.AddError("Invalid input", "source and event are required")
-        return
-    }
-
-    // find structs that match the source
-    var candidates []string
-    for name, sc := range client.Structs {
-        if sc.FixedSource != nil && *sc.FixedSource == src {
-            candidates = append(candidates, name)
-        }
-    }
-    if len(candidates) == 0 {
-        resp.Diagnostics.AddError("Unknown source", "No structs found with fixed source = "+src)
+    if data.Source.ValueString() == "" {
+        resp.Diagnostics.AddError("Invalid input", "source is required")
         return
     }
-    sort.Strings(candidates)
-
-    // pick the first struct that allows the event
-    var chosen string
-    for _, sname := range candidates {
-        allowed, _, err := client.AllowedEventsForStruct(sname)
-        if err != nil { resp.Diagnostics.AddError("Lookup error", err.Error()); return }
-        for _, a := range allowed { if a == ev { chosen = sname; break } }
-        if chosen != "" { break }
-    }
-    if chosen == "" {
-        resp.Diagnostics.AddError("Invalid event", "event "+ev+" is not allowed for source "+src)
-        return
-    }
-
-    // build pattern using canonical keys
-    allowed, _, err := client.AllowedEventsForStruct(chosen)
-    if err != nil { resp.Diagnostics.AddError("Lookup error", err.Error()); return }
-    ok := false
-    for _, a := range allowed { if a == ev { ok = true; break } }
-    if !ok {
-        resp.Diagnostics.AddError("Invalid event", "event "+ev+" is not allowed for struct "+chosen)
-        return
-    }
-    evtKey, okk := client.Keys["event"]
-    if !okk { resp.Diagnostics.AddError("Missing key", "'event' key missing from catalog"); return }
-    parts := []string{fmt.Sprintf("$.%s = \"%s\"", evtKey, ev)}
-    if srcVal, fixed, err := client.FixedSourceForStruct(chosen); err == nil && fixed {
-        if srcKey, ok2 := client.Keys["source"]; ok2 {
-            parts = append(parts, fmt.Sprintf("$.%s = \"%s\"", srcKey, srcVal))
-        } else {
-            resp.Diagnostics.AddError("Missing key", "'source' key missing from catalog")
-            return
-        }
-    }
-    pat := fmt.Sprintf("{ %s }", strings.Join(parts, " && "))
-    data.Pattern = types.StringValue(pat)
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, data.Predicates))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+    data.Struct = resolved
+    data.Pattern = types.StringValue(renderCloudWatch(sel.Expr))
 
     diags = resp.State.Set(ctx, &data)
     resp.Diagnostics.Append(diags...)
@@ -0,0 +1,81 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/cwpattern"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type patternLintDataSource struct{ client *MetadataClient }
+
+func NewPatternLintDataSource() datasource.DataSource { return &patternLintDataSource{} }
+
+type patternLintModel struct {
+    Pattern  types.String   `tfsdk:"pattern"`
+    Severity types.String   `tfsdk:"severity"`
+    Valid    types.Bool     `tfsdk:"valid"`
+    Problems []types.String `tfsdk:"problems"`
+}
+
+func (d *patternLintDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_pattern_lint"
+}
+
+func (d *patternLintDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: map[string]schema.Attribute{
+            "pattern":  schema.StringAttribute{Required: true, Description: "Hand-written CloudWatch Logs filter pattern to check"},
+            "severity": schema.StringAttribute{Optional: true, Description: "Report problems as warning or error diagnostics (default warning)"},
+            "valid":    schema.BoolAttribute{Computed: true, Description: "Whether no problems were found"},
+            "problems": schema.ListAttribute{Computed: true, ElementType: types.StringType, Description: "Problems found, each prefixed with the column of the offending selector"},
+        },
+    }
+}
+
+func (d *patternLintDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *patternLintDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data patternLintModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    severity := "warning"
+    if !data.Severity.IsNull() { severity = data.Severity.ValueString() }
+    if severity != "warning" && severity != "error" {
+        resp.Diagnostics.AddAttributeError(path.Root("severity"), "Invalid severity", "severity must be warning or error, got "+severity)
+        return
+    }
+    p, err := cwpattern.Parse(data.Pattern.ValueString())
+    if err != nil {
+        resp.Diagnostics.AddAttributeError(path.Root("pattern"), "Malformed pattern", err.Error())
+        return
+    }
+
+    findings := client.lintPattern(p)
+    data.Problems = []types.String{}
+    for _, f := range findings {
+        data.Problems = append(data.Problems, types.StringValue(f.String()))
+        if severity == "error" {
+            resp.Diagnostics.AddAttributeError(path.Root("pattern"), f.Summary, f.String())
+        } else {
+            resp.Diagnostics.AddAttributeWarning(path.Root("pattern"), f.Summary, f.String())
+        }
+    }
+    data.Valid = types.BoolValue(len(findings) == 0)
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
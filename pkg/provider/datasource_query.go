@@ -0,0 +1,114 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type queryDataSource struct{ client *MetadataClient }
+
+func NewQueryDataSource() datasource.DataSource { return &queryDataSource{} }
+
+type queryModel struct {
+    Source            types.String     `tfsdk:"source"`
+    Struct            types.String     `tfsdk:"struct"`
+    Event             types.String     `tfsdk:"event"`
+    Events            []types.String   `tfsdk:"events"`
+    ExcludeEvents     []types.String   `tfsdk:"exclude_events"`
+    Predicates        []predicateModel `tfsdk:"predicates"`
+    All               types.List       `tfsdk:"all"`
+    Any               types.List       `tfsdk:"any"`
+    Not               types.List       `tfsdk:"not"`
+    CloudWatchPattern types.String     `tfsdk:"cloudwatch_pattern"`
+    InsightsQuery     types.String     `tfsdk:"insights_query"`
+    DatadogQuery      types.String     `tfsdk:"datadog_query"`
+    LokiPipeline      types.String     `tfsdk:"loki_pipeline"`
+    OpenSearchQuery   types.String     `tfsdk:"opensearch_query"`
+    SplunkSearch      types.String     `tfsdk:"splunk_search"`
+    GCPLoggingFilter  types.String     `tfsdk:"gcp_logging_filter"`
+    KQLFilter         types.String     `tfsdk:"kql_filter"`
+    NRQLWhere         types.String     `tfsdk:"nrql_where"`
+    VectorCondition   types.String     `tfsdk:"vector_condition"`
+    FluentBitGrep     types.String     `tfsdk:"fluentbit_grep"`
+}
+
+func (d *queryDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_query"
+}
+
+func (d *queryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: selectorAttributes(map[string]schema.Attribute{
+            "cloudwatch_pattern": schema.StringAttribute{Computed: true, Description: "CloudWatch Logs filter pattern"},
+            "insights_query":     schema.StringAttribute{Computed: true, Description: "CloudWatch Logs Insights filter command"},
+            "datadog_query":      schema.StringAttribute{Computed: true, Description: "Datadog log search query"},
+            "loki_pipeline":      schema.StringAttribute{Computed: true, Description: "LogQL pipeline to append to a stream selector"},
+            "opensearch_query":   schema.StringAttribute{Computed: true, Description: "OpenSearch query DSL clause as JSON"},
+            "splunk_search":      schema.StringAttribute{Computed: true, Description: "Splunk SPL search terms"},
+            "gcp_logging_filter": schema.StringAttribute{Computed: true, Description: "Cloud Logging filter over jsonPayload, comparing level in the payload"},
+            "kql_filter":         schema.StringAttribute{Computed: true, Description: "KQL where predicate over parse_json(Message)"},
+            "nrql_where":         schema.StringAttribute{Computed: true, Description: "NRQL WHERE condition"},
+            "vector_condition":   schema.StringAttribute{Computed: true, Description: "Vector VRL condition"},
+            "fluentbit_grep":     schema.StringAttribute{Computed: true, Description: "Fluent Bit grep [FILTER] section; null when the condition cannot be expressed with grep rules"},
+        }),
+        Blocks: conditionBlocks(conditionDepth),
+    }
+}
+
+func (d *queryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *queryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data queryModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, nil))
+    resp.Diagnostics.Append(diags...)
+    clauses, diags := client.conditionClauses(ctx, data.Predicates, map[string]types.List{"all": data.All, "any": data.Any, "not": data.Not}, path.Empty())
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+    for _, e := range clauses { sel.Expr = sel.Expr.and(e) }
+
+    data.Struct = resolved
+    data.CloudWatchPattern = types.StringValue(renderCloudWatch(sel.Expr))
+    data.InsightsQuery = types.StringValue(insightsQuery{Filter: sel.Expr}.String())
+    data.DatadogQuery = types.StringValue(renderDatadog(sel.Expr))
+    data.LokiPipeline = types.StringValue(lokiPipeline(sel.Expr))
+    q, err := json.Marshal(opensearchQuery(opensearchFields{}, sel.Expr))
+    if err != nil { resp.Diagnostics.AddError("Render error", err.Error()); return }
+    data.OpenSearchQuery = types.StringValue(string(q))
+    data.SplunkSearch = types.StringValue(splunkSearch{Filter: sel.Expr}.String())
+    data.KQLFilter = types.StringValue(kqlQuery{MessageColumn: "Message"}.where(sel.Expr))
+    data.NRQLWhere = types.StringValue(nrqlExpr(sel.Expr, false))
+    data.VectorCondition = types.StringValue(renderVRL(sel.Expr))
+
+    // level stays in the payload: mapping it to severity needs agent configuration
+    gcp, err := gcpFilter{Payload: "jsonPayload"}.render(sel.Expr)
+    if err != nil { resp.Diagnostics.AddError("Render error", err.Error()); return }
+    data.GCPLoggingFilter = types.StringValue(gcp)
+
+    // dialects that cannot express every condition are left null
+    data.FluentBitGrep = types.StringNull()
+    fb := fluentBitGrep{Match: "*"}
+    if rules, err := fb.rules(sel.Expr); err == nil {
+        fb.Rules = rules
+        data.FluentBitGrep = types.StringValue(fb.String())
+    }
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
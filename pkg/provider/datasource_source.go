@@ -58,17 +58,11 @@ func (d *sourceDataSource) Read(ctx context.Context, req datasource.ReadRequest,
     }
 
     // Collect all structs that have this fixed source
-    var structs []string
-    for name, sc := range client.Structs {
-        if sc.FixedSource != nil && *sc.FixedSource == src {
-            structs = append(structs, name)
-        }
-    }
+    structs := client.StructsForSource(src)
     if len(structs) == 0 {
         resp.Diagnostics.AddError("Unknown source", "No structs found with fixed source = "+src)
         return
     }
-    sort.Strings(structs)
 
     // Union of events across all matching structs
     evset := map[string]struct{}{}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type splunkSearchDataSource struct{ client *MetadataClient }
+
+func NewSplunkSearchDataSource() datasource.DataSource { return &splunkSearchDataSource{} }
+
+type splunkSearchModel struct {
+    Source        types.String     `tfsdk:"source"`
+    Struct        types.String     `tfsdk:"struct"`
+    Event         types.String     `tfsdk:"event"`
+    Events        []types.String   `tfsdk:"events"`
+    ExcludeEvents []types.String   `tfsdk:"exclude_events"`
+    Predicates    []predicateModel `tfsdk:"predicates"`
+    Index         types.String     `tfsdk:"index"`
+    Sourcetype    types.String     `tfsdk:"sourcetype"`
+    Stats         *statsModel      `tfsdk:"stats"`
+    Search        types.String     `tfsdk:"search"`
+}
+
+func (d *splunkSearchDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = "logstruct_splunk_search"
+}
+
+func (d *splunkSearchDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        Attributes: selectorAttributes(map[string]schema.Attribute{
+            "index":      schema.StringAttribute{Optional: true, Description: "Splunk index to search"},
+            "sourcetype": schema.StringAttribute{Optional: true, Description: "Splunk sourcetype to search"},
+            "search":     schema.StringAttribute{Computed: true, Description: "Compiled SPL search"},
+        }),
+        Blocks: map[string]schema.Block{
+            "predicates": predicatesBlock(),
+            "stats": schema.SingleNestedBlock{
+                Description: "Aggregation rendered as a stats command",
+                Attributes:  statsAttributes(nil),
+            },
+        },
+    }
+}
+
+func (d *splunkSearchDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+    if req.ProviderData == nil { return }
+    if c, ok := req.ProviderData.(*MetadataClient); ok { d.client = c }
+}
+
+func (d *splunkSearchDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    var data splunkSearchModel
+    diags := req.Config.Get(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    client := d.client
+    if client == nil {
+        resp.Diagnostics.AddError("Provider not configured", "Missing metadata client")
+        return
+    }
+    sel, resolved, diags := client.compileSelector(selectorConfigOf(data.Source, data.Struct, data.Event, data.Events, data.ExcludeEvents, data.Predicates))
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() { return }
+
+    s := splunkSearch{Index: data.Index.ValueString(), Sourcetype: data.Sourcetype.ValueString(), Filter: sel.Expr}
+    if data.Stats != nil {
+        agg, diags := client.aggregation(*data.Stats, path.Root("stats"), client.SerializedKey)
+        resp.Diagnostics.Append(diags...)
+        if resp.Diagnostics.HasError() { return }
+        s.Stats = &agg
+    }
+    data.Struct = resolved
+    data.Search = types.StringValue(s.String())
+
+    diags = resp.State.Set(ctx, &data)
+    resp.Diagnostics.Append(diags...)
+}
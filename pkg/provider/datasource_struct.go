@@ -18,6 +18,15 @@ type structDataModel struct {
     FixedSource   types.String   `tfsdk:"fixed_source"`
     AllowedEvents []types.String `tfsdk:"allowed_events"`
     Keys          types.Map      `tfsdk:"keys"`
+    Fields        []fieldModel   `tfsdk:"fields"`
+}
+
+type fieldModel struct {
+    Name     types.String `tfsdk:"name"`
+    Key      types.String `tfsdk:"key"`
+    Type     types.String `tfsdk:"type"`
+    Kind     types.String `tfsdk:"kind"`
+    Optional types.Bool   `tfsdk:"optional"`
 }
 
 func (d *structDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -30,7 +39,20 @@ func (d *structDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
             "struct": schema.StringAttribute{Required: true, Description: "LogStruct struct name e.g. ActionMailer"},
             "fixed_source": schema.StringAttribute{Computed: true},
             "allowed_events": schema.ListAttribute{Computed: true, ElementType: types.StringType},
-            "keys": schema.MapAttribute{Computed: true, ElementType: types.StringType},
+            "keys": schema.MapAttribute{Computed: true, ElementType: types.StringType, Description: "Map of canonical key names to serialized keys for the fields this struct emits"},
+            "fields": schema.ListNestedAttribute{
+                Computed:    true,
+                Description: "Fields emitted by this struct, in declaration order",
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "name":     schema.StringAttribute{Computed: true, Description: "Canonical key name (e.g., mailer_action)"},
+                        "key":      schema.StringAttribute{Computed: true, Description: "Serialized JSON key (e.g., mailer for mailer_class)"},
+                        "type":     schema.StringAttribute{Computed: true, Description: "Field type as declared in LogStruct"},
+                        "kind":     schema.StringAttribute{Computed: true, Description: "JSON kind of the value: string, integer, float, boolean, array, object, timestamp or any"},
+                        "optional": schema.BoolAttribute{Computed: true, Description: "Whether the field may be omitted"},
+                    },
+                },
+            },
         },
     }
 }
@@ -64,9 +86,21 @@ func (d *structDataSource) Read(ctx context.Context, req datasource.ReadRequest,
     data.AllowedEvents = []types.String{}
     for _, e := range allowed { data.AllowedEvents = append(data.AllowedEvents, types.StringValue(e)) }
     _ = single
-    // keys map
+    // fields and the keys they serialize to
+    fields, err := client.FieldsForStruct(data.Struct.ValueString())
+    if err != nil { resp.Diagnostics.AddError("Lookup error", err.Error()); return }
+    data.Fields = []fieldModel{}
     kv := map[string]attr.Value{}
-    for k, v := range client.Keys { kv[k] = types.StringValue(v) }
+    for _, f := range fields {
+        data.Fields = append(data.Fields, fieldModel{
+            Name:     types.StringValue(f.Name),
+            Key:      types.StringValue(f.Key),
+            Type:     types.StringValue(f.Type),
+            Kind:     types.StringValue(f.Kind),
+            Optional: types.BoolValue(f.Optional),
+        })
+        kv[f.Name] = types.StringValue(f.Key)
+    }
     m, md := types.MapValue(types.StringType, kv)
     resp.Diagnostics.Append(md...)
     data.Keys = m
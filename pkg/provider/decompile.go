@@ -0,0 +1,261 @@
+package provider
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/cwpattern"
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/data"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// decompiled is the logstruct_pattern configuration recovered from a pattern.
+type decompiled struct {
+    Source        string
+    Struct        string
+    Events        []string
+    ExcludeEvents []string
+    Predicates    []decompiledPredicate
+}
+
+type decompiledPredicate struct {
+    Key    string // canonical key
+    Op     string
+    Values []string
+}
+
+// Decompile translates a CloudWatch Logs JSON filter pattern into a
+// logstruct_pattern data source block labelled name, resolving the source,
+// events and struct against the catalog. When clauses cannot be expressed as
+// logstruct_pattern configuration, the error explains each of them.
+func (c *MetadataClient) Decompile(name, pattern string) (string, error) {
+    p, err := cwpattern.Parse(pattern)
+    if err != nil { return "", err }
+    if p.Kind != cwpattern.JSONPattern { return "", errors.New("only JSON patterns such as { $.evt = \"finish\" } can be decompiled; LogStruct logs are JSON") }
+
+    canonical := map[string]string{}
+    for name, key := range c.Keys { canonical[key] = name }
+    evtKey, srcKey := c.Keys["event"], c.Keys["source"]
+
+    var d decompiled
+    var problems []error
+    unsupported := func(e cwpattern.Expr, format string, args ...any) {
+        pos, _ := e.Span()
+        problems = append(problems, fmt.Errorf("column %d: %s: %s", pos+1, p.Text(e), fmt.Sprintf(format, args...)))
+    }
+    for _, clause := range conjuncts(p.Expr) {
+        // boolean predicates compile to IS TRUE and IS FALSE tests
+        if chk, ok := clause.(*cwpattern.Check); ok && (chk.Op == "IS TRUE" || chk.Op == "IS FALSE") {
+            name, ok := canonical[chk.Selector.Key()]
+            if !ok || len(chk.Selector.Path) != 1 || chk.Selector.Path[0].Array || c.KeyKind(name) != data.KindBoolean {
+                unsupported(clause, "predicates only test top-level boolean keys with %s", chk.Op)
+                continue
+            }
+            d.addPredicate(name, opEqual, []string{strings.ToLower(strings.TrimPrefix(chk.Op, "IS "))})
+            continue
+        }
+        cmps, why := comparisonsOf(clause)
+        if why != "" { unsupported(clause, "%s", why); continue }
+        sel, op := cmps[0].Selector, cmps[0].Op
+        if len(sel.Path) != 1 || sel.Path[0].Array { unsupported(clause, "predicates compare top-level keys; nested selectors cannot be expressed"); continue }
+        key := sel.Key()
+        name, ok := canonical[key]
+        if !ok { unsupported(clause, "LogStruct does not emit a key named %q", key); continue }
+
+        values := make([]string, 0, len(cmps))
+        exact, numbers, regex := true, 0, false
+        for _, cmp := range cmps {
+            switch cmp.Value.Kind {
+            case cwpattern.Regex:
+                regex = true
+            case cwpattern.Number:
+                numbers++
+            }
+            if strings.Contains(cmp.Value.Text, "*") { exact = false }
+            values = append(values, cmp.Value.Text)
+        }
+        if regex { unsupported(clause, "regular expression values cannot be expressed"); continue }
+        if numbers != 0 && numbers != len(cmps) { unsupported(clause, "%s is compared with both numbers and strings", key); continue }
+
+        switch {
+        case exact && numbers == 0 && key == srcKey && op == opEqual && len(values) == 1 && d.Source == "":
+            d.Source = values[0]
+            continue
+        case exact && numbers == 0 && key == evtKey && op == opEqual && d.Events == nil:
+            d.Events = values
+            continue
+        case exact && numbers == 0 && key == evtKey && op == opNotEqual:
+            // an exclusion unless events are selected, which is only known
+            // once every clause is read
+            if !contains(d.ExcludeEvents, values[0]) { d.ExcludeEvents = append(d.ExcludeEvents, values[0]) }
+            continue
+        }
+        if why := c.decompilePredicate(name, op, values, numbers > 0); why != "" { unsupported(clause, "%s", why); continue }
+        d.addPredicate(name, op, values)
+    }
+    if len(d.Events) > 0 && len(d.ExcludeEvents) > 0 {
+        d.addPredicate("event", opNotEqual, d.ExcludeEvents)
+        d.ExcludeEvents = nil
+    }
+    if d.Source == "" {
+        problems = append(problems, errors.New(c.missingSource(d.Events)))
+    }
+    if len(problems) > 0 { return "", errors.Join(problems...) }
+
+    // validate the recovered configuration the way the data source would,
+    // pinning the struct when the catalog resolves it unambiguously
+    cfg := selectorConfig{Source: types.StringValue(d.Source), Struct: types.StringNull(), Event: types.StringNull()}
+    for _, ev := range d.Events { cfg.Events = append(cfg.Events, types.StringValue(ev)) }
+    for _, ev := range d.ExcludeEvents { cfg.ExcludeEvents = append(cfg.ExcludeEvents, types.StringValue(ev)) }
+    for _, pr := range d.Predicates {
+        pm := predicateModel{Key: types.StringValue(pr.Key), Operator: types.StringValue(pr.Op), Value: types.StringNull()}
+        for _, v := range pr.Values { pm.Values = append(pm.Values, types.StringValue(v)) }
+        cfg.Predicates = append(cfg.Predicates, pm)
+    }
+    _, resolved, diags := c.compileSelector(cfg)
+    if diags.HasError() {
+        for _, e := range diags.Errors() { problems = append(problems, errors.New(e.Detail())) }
+        return "", errors.Join(problems...)
+    }
+    if len(c.StructsForSource(d.Source)) > 1 && !resolved.IsNull() && diags.WarningsCount() == 0 { d.Struct = resolved.ValueString() }
+    return d.hcl(name), nil
+}
+
+// decompilePredicate reports why a comparison cannot be expressed as a
+// predicate, or "" when the predicate compiles to the same comparison.
+func (c *MetadataClient) decompilePredicate(canonical, op string, values []string, number bool) string {
+    e, err := c.predicate(canonical, op, values)
+    if err != nil { return err.Error() }
+    var leaf cmpExpr
+    switch n := e.(type) {
+    case cmpExpr:
+        leaf = n
+    case anyExpr:
+        leaf = n[0].(cmpExpr)
+    case allExpr:
+        leaf = n[0].(cmpExpr)
+    }
+    numeric := leaf.Numeric
+    switch {
+    case leaf.Boolean:
+        return fmt.Sprintf("%q holds boolean values, so logstruct_pattern would test it with IS TRUE or IS FALSE rather than compare it with a string", canonical)
+    case numeric && !number:
+        return fmt.Sprintf("%q holds %s values, so logstruct_pattern would compare it with a number rather than a string", canonical, c.KeyKind(canonical))
+    case !numeric && number:
+        return fmt.Sprintf("logstruct_pattern compares %q with strings, so it would quote the number", canonical)
+    }
+    return ""
+}
+
+// missingSource explains that logstruct_pattern selects a source, naming the
+// sources whose structs emit events.
+func (c *MetadataClient) missingSource(events []string) string {
+    msg := "logstruct_pattern selects a source, but the pattern has no exact $." + c.Keys["source"] + " = \"...\" clause"
+    if len(events) == 0 { return msg }
+    seen := map[string]bool{}
+    var sources []string
+    for _, sc := range c.Structs {
+        if sc.FixedSource == nil || seen[*sc.FixedSource] { continue }
+        all := true
+        for _, ev := range events { if !contains(sc.AllowedEvents, ev) { all = false } }
+        if all { seen[*sc.FixedSource] = true; sources = append(sources, *sc.FixedSource) }
+    }
+    if len(sources) == 0 { return msg }
+    sort.Strings(sources)
+    return fmt.Sprintf("%s; event %s is emitted by source %s", msg, strings.Join(events, ", "), strings.Join(sources, ", "))
+}
+
+// addPredicate appends a predicate, merging != comparisons on one key into a
+// single predicate whose values are ANDed.
+func (d *decompiled) addPredicate(key, op string, values []string) {
+    if op == opNotEqual {
+        for i, pr := range d.Predicates {
+            if pr.Key == key && pr.Op == opNotEqual { d.Predicates[i].Values = append(pr.Values, values...); return }
+        }
+    }
+    d.Predicates = append(d.Predicates, decompiledPredicate{Key: key, Op: op, Values: values})
+}
+
+// conjuncts flattens the && chains of e into their clauses.
+func conjuncts(e cwpattern.Expr) []cwpattern.Expr {
+    if l, ok := e.(*cwpattern.Logical); ok && l.Op == "&&" { return append(conjuncts(l.X), conjuncts(l.Y)...) }
+    return []cwpattern.Expr{e}
+}
+
+// comparisonsOf returns the comparisons of a clause that a single predicate
+// can express: one comparison, or = comparisons on one selector joined by ||.
+// Otherwise it explains why the clause cannot be expressed.
+func comparisonsOf(e cwpattern.Expr) ([]*cwpattern.Comparison, string) {
+    switch n := e.(type) {
+    case *cwpattern.Comparison:
+        return []*cwpattern.Comparison{n}, ""
+    case *cwpattern.Check:
+        return nil, "predicates cannot test " + n.Op
+    }
+    var leaves []cwpattern.Expr
+    var walk func(cwpattern.Expr)
+    walk = func(e cwpattern.Expr) {
+        if l, ok := e.(*cwpattern.Logical); ok && l.Op == "||" { walk(l.X); walk(l.Y); return }
+        leaves = append(leaves, e)
+    }
+    walk(e)
+    var cmps []*cwpattern.Comparison
+    for _, leaf := range leaves {
+        cmp, ok := leaf.(*cwpattern.Comparison)
+        if !ok || cmp.Op != opEqual { return nil, "only = comparisons on one key can be joined with ||" }
+        if len(cmps) > 0 && cmp.Selector.Text != cmps[0].Selector.Text { return nil, "only = comparisons on one key can be joined with ||" }
+        cmps = append(cmps, cmp)
+    }
+    return cmps, ""
+}
+
+// hcl renders the configuration as a data source block, aligned the way
+// terraform fmt aligns it.
+func (d decompiled) hcl(name string) string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "data \"logstruct_pattern\" %s {\n", hclString(name))
+    attrs := [][2]string{{"source", hclString(d.Source)}}
+    if d.Struct != "" { attrs = append(attrs, [2]string{"struct", hclString(d.Struct)}) }
+    switch {
+    case len(d.Events) == 1:
+        attrs = append(attrs, [2]string{"event", hclString(d.Events[0])})
+    case len(d.Events) > 1:
+        attrs = append(attrs, [2]string{"events", hclList(d.Events)})
+    }
+    if len(d.ExcludeEvents) > 0 { attrs = append(attrs, [2]string{"exclude_events", hclList(d.ExcludeEvents)}) }
+    writeHCLAttributes(&b, "  ", attrs)
+    for _, pr := range d.Predicates {
+        attrs := [][2]string{{"key", hclString(pr.Key)}}
+        if pr.Op != opEqual { attrs = append(attrs, [2]string{"operator", hclString(pr.Op)}) }
+        if len(pr.Values) == 1 {
+            attrs = append(attrs, [2]string{"value", hclString(pr.Values[0])})
+        } else {
+            attrs = append(attrs, [2]string{"values", hclList(pr.Values)})
+        }
+        b.WriteString("\n  predicates {\n")
+        writeHCLAttributes(&b, "    ", attrs)
+        b.WriteString("  }\n")
+    }
+    b.WriteString("}\n")
+    return b.String()
+}
+
+func writeHCLAttributes(b *strings.Builder, indent string, attrs [][2]string) {
+    width := 0
+    for _, a := range attrs { if len(a[0]) > width { width = len(a[0]) } }
+    for _, a := range attrs { fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, a[0], a[1]) }
+}
+
+// hclString quotes s as an HCL string, escaping template sequences.
+func hclString(s string) string {
+    s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "${", "$${", "%{", "%%{").Replace(s)
+    return `"` + s + `"`
+}
+
+func hclList(values []string) string {
+    quoted := make([]string, 0, len(values))
+    for _, v := range values { quoted = append(quoted, hclString(v)) }
+    return "[" + strings.Join(quoted, ", ") + "]"
+}
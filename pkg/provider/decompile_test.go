@@ -0,0 +1,66 @@
+package provider
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestDecompile(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+
+    got, err := c.Decompile("job_finish", `{ $.src = "job" && $.evt = "finish" && $.queue_name = "default" }`)
+    if err != nil { t.Fatalf("decompile: %v", err) }
+    want := `data "logstruct_pattern" "job_finish" {
+  source = "job"
+  event  = "finish"
+
+  predicates {
+    key   = "queue_name"
+    value = "default"
+  }
+}
+`
+    if got != want { t.Errorf("got\n%s\nwant\n%s", got, want) }
+
+    got, err = c.Decompile("x", `{ $.src = "job" && ($.evt = "finish" || $.evt = "start") && $.evt != "enqueue" && $.duration_ms > 100 }`)
+    if err != nil { t.Fatalf("decompile: %v", err) }
+    for _, want := range []string{`events = ["finish", "start"]`, `operator = ">"`, `value    = "100"`, `key      = "event"`} {
+        if !strings.Contains(got, want) { t.Errorf("missing %q in\n%s", want, got) }
+    }
+
+    got, err = c.Decompile("x", `{ $.src = "mailer" && $.evt != "delivered" }`)
+    if err != nil { t.Fatalf("decompile: %v", err) }
+    if !strings.Contains(got, `exclude_events = ["delivered"]`) { t.Errorf("expected exclude_events, got\n%s", got) }
+
+    got, err = c.Decompile("x", `{ $.src = "storage" && $.evt = "exist" && $.exist IS FALSE }`)
+    if err != nil { t.Fatalf("decompile: %v", err) }
+    for _, want := range []string{`key   = "exist"`, `value = "false"`} {
+        if !strings.Contains(got, want) { t.Errorf("missing %q in\n%s", want, got) }
+    }
+
+    cases := []struct {
+        pattern string
+        want    []string // explanations, in order
+    }{
+        {`ERROR`, []string{"only JSON patterns"}},
+        {`{ $.evt = `, []string{"column 11: expected value"}},
+        {`{ $.evt = "finish" }`, []string{"no exact $.src", "emitted by source job"}},
+        {`{ $.src = "job" && $.error EXISTS }`, []string{`column 20: $.error EXISTS: predicates cannot test EXISTS`}},
+        {`{ $.src = "job" && $.queue_name = %def% }`, []string{"regular expression values cannot be expressed"}},
+        {`{ $.src = "job" && ($.queue_name = "a" || $.evt = "finish") }`, []string{"only = comparisons on one key can be joined with ||"}},
+        {`{ $.src = "job" && $.job.id = "1" }`, []string{"nested selectors cannot be expressed"}},
+        {`{ $.src = "job" && $.status = "500" }`, []string{`"status" holds integer values`}},
+        {`{ $.src = "storage" && $.exist = "true" }`, []string{`"exist" holds boolean values, so logstruct_pattern would test it with IS TRUE`}},
+        {`{ $.src = "job" && $.queue_name IS TRUE }`, []string{"predicates only test top-level boolean keys with IS TRUE"}},
+        {`{ $.src = "job" && $.evtt = "finish" }`, []string{`LogStruct does not emit a key named "evtt"`}},
+        {`{ $.src = "job" && $.evt = "delivered" }`, []string{"event delivered is not allowed for source job"}},
+    }
+    for _, tc := range cases {
+        _, err := c.Decompile("x", tc.pattern)
+        if err == nil { t.Errorf("%s: expected an error", tc.pattern); continue }
+        for _, want := range tc.want {
+            if !strings.Contains(err.Error(), want) { t.Errorf("%s: got %q, want %q", tc.pattern, err, want) }
+        }
+    }
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+    "fmt"
+    "strings"
+)
+
+// expr is a node in the backend-neutral filter tree that data sources compile
+// their inputs into. Renderers turn the tree into a concrete query dialect.
+type expr interface{ isExpr() }
+
+// allExpr matches when every child matches.
+type allExpr []expr
+
+// anyExpr matches when at least one child matches.
+type anyExpr []expr
+
+// cmpExpr compares the value at a serialized JSON key with a literal.
+type cmpExpr struct {
+    Key     string // serialized key as emitted by LogStruct, e.g. "evt"
+    Op      string
+    Value   string
+    Numeric bool // render Value unquoted
+    Boolean bool // Value is true or false, compared with a JSON boolean
+}
+
+// presentExpr matches when the log has the serialized key, whatever its value.
+type presentExpr struct{ Key string }
+
+func (allExpr) isExpr() {}
+func (anyExpr) isExpr() {}
+func (cmpExpr) isExpr() {}
+func (presentExpr) isExpr() {}
+
+// and appends e to a, flattening nested conjunctions.
+func (a allExpr) and(e expr) allExpr {
+    if nested, ok := e.(allExpr); ok { return append(a, nested...) }
+    return append(a, e)
+}
+
+// negatedOperators maps each comparison operator to its complement.
+var negatedOperators = map[string]string{
+    opEqual:        opNotEqual,
+    opNotEqual:     opEqual,
+    opGreater:      opLessEqual,
+    opGreaterEqual: opLess,
+    opLess:         opGreaterEqual,
+    opLessEqual:    opGreater,
+}
+
+// negate returns the negation of e. Not every dialect can negate a group, so
+// the negation is pushed down to the comparisons by De Morgan's laws. Like
+// any comparison, the negated ones never match a log lacking their key.
+func negate(e expr) expr {
+    switch n := e.(type) {
+    case allExpr:
+        out := make(anyExpr, 0, len(n))
+        for _, c := range n { out = append(out, negate(c)) }
+        return out
+    case anyExpr:
+        out := make(allExpr, 0, len(n))
+        for _, c := range n { out = out.and(negate(c)) }
+        return out
+    case cmpExpr:
+        n.Op = negatedOperators[n.Op]
+        return n
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+// requirePresent returns e with each comparison guarded reports true for
+// preceded by a presentExpr on its key. Every dialect follows CloudWatch: a
+// comparison on a key the log lacks never matches, != and negated groups
+// included. Renderers whose dialect matches missing keys for some
+// comparisons, as a negated term does, apply this before rendering.
+func requirePresent(e expr, guarded func(cmpExpr) bool) expr {
+    switch n := e.(type) {
+    case allExpr:
+        out := make(allExpr, 0, len(n))
+        seen := map[string]bool{}
+        for _, c := range n {
+            if cmp, ok := c.(cmpExpr); ok && guarded(cmp) {
+                if !seen[cmp.Key] { out = append(out, presentExpr{Key: cmp.Key}); seen[cmp.Key] = true }
+                out = append(out, cmp)
+                continue
+            }
+            out = out.and(requirePresent(c, guarded))
+        }
+        return out
+    case anyExpr:
+        out := make(anyExpr, 0, len(n))
+        for _, c := range n { out = append(out, requirePresent(c, guarded)) }
+        return out
+    case cmpExpr:
+        if guarded(n) { return allExpr{presentExpr{Key: n.Key}, n} }
+        return n
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+// notEqual reports whether c is a != comparison, which most dialects match
+// on logs lacking the key.
+func notEqual(c cmpExpr) bool { return c.Op == opNotEqual }
+
+// renderCloudWatch renders e as a CloudWatch Logs JSON filter pattern.
+func renderCloudWatch(e expr) string {
+    return fmt.Sprintf("{ %s }", cloudWatchExpr(e, false))
+}
+
+func cloudWatchExpr(e expr, nested bool) string {
+    switch n := e.(type) {
+    case allExpr:
+        return cloudWatchJoin(n, " && ", nested)
+    case anyExpr:
+        return cloudWatchJoin(n, " || ", nested)
+    case cmpExpr:
+        // booleans are only tested with IS TRUE and IS FALSE
+        if n.Boolean {
+            if (n.Value == "true") == (n.Op == opEqual) { return "$." + n.Key + " IS TRUE" }
+            return "$." + n.Key + " IS FALSE"
+        }
+        return fmt.Sprintf("$.%s %s %s", n.Key, n.Op, cloudWatchLiteral(n))
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func cloudWatchJoin(children []expr, sep string, nested bool) string {
+    parts := make([]string, 0, len(children))
+    for _, c := range children { parts = append(parts, cloudWatchExpr(c, true)) }
+    s := strings.Join(parts, sep)
+    if nested && len(parts) > 1 { return "(" + s + ")" }
+    return s
+}
+
+func cloudWatchLiteral(c cmpExpr) string {
+    if c.Numeric { return c.Value }
+    return quoteDouble(c.Value)
+}
+
+// quoteDouble wraps s in double quotes, escaping backslashes and quotes.
+func quoteDouble(s string) string {
+    s = strings.ReplaceAll(s, `\`, `\\`)
+    return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+type keyFunction struct{}
+
+func NewKeyFunction() function.Function { return &keyFunction{} }
+
+func (f *keyFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+    resp.Name = "key"
+}
+
+func (f *keyFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+    resp.Definition = function.Definition{
+        Summary:     "Look up a serialized key",
+        Description: "Returns the JSON key LogStruct serializes a canonical key name to (e.g., http_method returns method).",
+        Parameters: []function.Parameter{
+            function.StringParameter{Name: "canonical", Description: "Canonical key name from the catalog"},
+        },
+        Return: function.StringReturn{},
+    }
+}
+
+func (f *keyFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+    var canonical string
+    resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &canonical))
+    if resp.Error != nil { return }
+
+    client, err := NewMetadataClient()
+    if err != nil { resp.Error = function.NewFuncError(err.Error()); return }
+    key, err := client.SerializedKey(canonical)
+    if err != nil { resp.Error = function.NewArgumentFuncError(0, err.Error()); return }
+    resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, key))
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/cwpattern"
+    "github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+type matchesFunction struct{}
+
+func NewMatchesFunction() function.Function { return &matchesFunction{} }
+
+func (f *matchesFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+    resp.Name = "matches"
+}
+
+func (f *matchesFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+    resp.Definition = function.Definition{
+        Summary:     "Evaluate a CloudWatch filter pattern against a log event",
+        Description: "Returns whether a CloudWatch Logs filter pattern matches a log event, such as a jsonencode'd LogStruct log line. Patterns that cannot be parsed are an error.",
+        Parameters: []function.Parameter{
+            function.StringParameter{Name: "pattern", Description: "CloudWatch Logs filter pattern (term, JSON or space-delimited)"},
+            function.StringParameter{Name: "event", Description: "Log event to test, e.g. jsonencode({evt = \"delivered\", src = \"mailer\"})"},
+        },
+        Return: function.BoolReturn{},
+    }
+}
+
+func (f *matchesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+    var pattern, event string
+    resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &pattern, &event))
+    if resp.Error != nil { return }
+
+    p, err := cwpattern.Parse(pattern)
+    if err != nil { resp.Error = function.NewArgumentFuncError(0, "Invalid filter pattern: "+err.Error()); return }
+    resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, p.Match(event)))
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/function"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+type patternFunction struct{}
+
+func NewPatternFunction() function.Function { return &patternFunction{} }
+
+func (f *patternFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+    resp.Name = "pattern"
+}
+
+func (f *patternFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+    resp.Definition = function.Definition{
+        Summary:     "Compile a CloudWatch Logs filter pattern",
+        Description: "Validates a source and event against the embedded catalog and returns the CloudWatch Logs filter pattern matching them, as logstruct_pattern does.",
+        Parameters: []function.Parameter{
+            function.StringParameter{Name: "source", Description: "Canonical source value (e.g., mailer, job, rails, storage)"},
+            function.StringParameter{Name: "event", Description: "Serialized event value (e.g., delivered, finish, database)"},
+        },
+        Return: function.StringReturn{},
+    }
+}
+
+func (f *patternFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+    var src, ev string
+    resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &src, &ev))
+    if resp.Error != nil { return }
+
+    client, err := NewMetadataClient()
+    if err != nil { resp.Error = function.NewFuncError(err.Error()); return }
+    if ev == "" { resp.Error = function.NewArgumentFuncError(1, "event cannot be empty"); return }
+    sel, diags := client.resolveSelector(selectorInput{Source: src, Events: []string{ev}, EventsPath: path.Root("event")})
+    if diags.HasError() { resp.Error = function.FuncErrorFromDiags(ctx, diags); return }
+    resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, renderCloudWatch(sel.Expr)))
+}
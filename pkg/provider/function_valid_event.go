@@ -0,0 +1,45 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+type validEventFunction struct{}
+
+func NewValidEventFunction() function.Function { return &validEventFunction{} }
+
+func (f *validEventFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+    resp.Name = "valid_event"
+}
+
+func (f *validEventFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+    resp.Definition = function.Definition{
+        Summary:     "Check an event against a source",
+        Description: "Returns whether any struct with the given fixed source allows the event. Unknown sources are an error.",
+        Parameters: []function.Parameter{
+            function.StringParameter{Name: "source", Description: "Canonical source value (e.g., mailer, job, rails, storage)"},
+            function.StringParameter{Name: "event", Description: "Serialized event value"},
+        },
+        Return: function.BoolReturn{},
+    }
+}
+
+func (f *validEventFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+    var src, ev string
+    resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &src, &ev))
+    if resp.Error != nil { return }
+
+    client, err := NewMetadataClient()
+    if err != nil { resp.Error = function.NewFuncError(err.Error()); return }
+    structs := client.StructsForSource(src)
+    if len(structs) == 0 { resp.Error = function.NewArgumentFuncError(0, "No structs found with fixed source = "+src); return }
+    valid := false
+    for _, sname := range structs {
+        allowed, _, err := client.AllowedEventsForStruct(sname)
+        if err != nil { resp.Error = function.NewFuncError(err.Error()); return }
+        if contains(allowed, ev) { valid = true; break }
+    }
+    resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, valid))
+}
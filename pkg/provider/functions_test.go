@@ -0,0 +1,66 @@
+package provider
+
+import (
+    "context"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/function"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func runFunction(t *testing.T, f function.Function, ret attr.Value, args ...string) function.RunResponse {
+    t.Helper()
+    values := make([]attr.Value, 0, len(args))
+    for _, a := range args { values = append(values, types.StringValue(a)) }
+    resp := function.RunResponse{Result: function.NewResultData(ret)}
+    f.Run(context.Background(), function.RunRequest{Arguments: function.NewArgumentsData(values)}, &resp)
+    return resp
+}
+
+func TestPatternFunction(t *testing.T) {
+    resp := runFunction(t, NewPatternFunction(), types.StringUnknown(), "mailer", "delivered")
+    if resp.Error != nil { t.Fatalf("pattern: %v", resp.Error) }
+    if got, want := resp.Result.Value().(types.String).ValueString(), `{ $.evt = "delivered" && $.src = "mailer" }`; got != want {
+        t.Errorf("got %s, want %s", got, want)
+    }
+    if resp := runFunction(t, NewPatternFunction(), types.StringUnknown(), "mailer", "finish"); resp.Error == nil {
+        t.Errorf("expected error for invalid event")
+    }
+}
+
+func TestKeyFunction(t *testing.T) {
+    resp := runFunction(t, NewKeyFunction(), types.StringUnknown(), "http_method")
+    if resp.Error != nil { t.Fatalf("key: %v", resp.Error) }
+    if got := resp.Result.Value().(types.String).ValueString(); got != "method" { t.Errorf("got %s, want method", got) }
+    if resp := runFunction(t, NewKeyFunction(), types.StringUnknown(), "nope"); resp.Error == nil {
+        t.Errorf("expected error for unknown key")
+    }
+}
+
+func TestValidEventFunction(t *testing.T) {
+    for ev, want := range map[string]bool{"error": true, "bogus": false} {
+        resp := runFunction(t, NewValidEventFunction(), types.BoolUnknown(), "job", ev)
+        if resp.Error != nil { t.Fatalf("valid_event: %v", resp.Error) }
+        if got := resp.Result.Value().(types.Bool).ValueBool(); got != want { t.Errorf("%s: got %v, want %v", ev, got, want) }
+    }
+    if resp := runFunction(t, NewValidEventFunction(), types.BoolUnknown(), "nope", "start"); resp.Error == nil {
+        t.Errorf("expected error for unknown source")
+    }
+}
+
+func TestMatchesFunction(t *testing.T) {
+    pattern := `{ $.evt = "delivered" && $.src = "mailer" }`
+    for event, want := range map[string]bool{
+        `{"evt":"delivered","src":"mailer"}`: true,
+        `{"evt":"failed","src":"mailer"}`:    false,
+        `not json`:                           false,
+    } {
+        resp := runFunction(t, NewMatchesFunction(), types.BoolUnknown(), pattern, event)
+        if resp.Error != nil { t.Fatalf("matches: %v", resp.Error) }
+        if got := resp.Result.Value().(types.Bool).ValueBool(); got != want { t.Errorf("%s: got %v, want %v", event, got, want) }
+    }
+    if resp := runFunction(t, NewMatchesFunction(), types.BoolUnknown(), `{ $.evt = "delivered"`, `{}`); resp.Error == nil {
+        t.Errorf("expected error for unparseable pattern")
+    }
+}
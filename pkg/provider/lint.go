@@ -0,0 +1,183 @@
+package provider
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/cwpattern"
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/data"
+)
+
+// maxLintBranches bounds the alternatives the combination check expands a
+// pattern into; larger patterns are only checked clause by clause.
+const maxLintBranches = 64
+
+// lintFinding is a problem found in a hand-written pattern, at a byte offset
+// in the pattern.
+type lintFinding struct {
+    Pos     int
+    Summary string
+    Detail  string
+}
+
+func (f lintFinding) String() string { return fmt.Sprintf("column %d: %s", f.Pos+1, f.Detail) }
+
+// lintPattern checks a parsed pattern against the catalog: selector keys
+// LogStruct never emits, event and source values no struct allows, literals
+// of the wrong kind, and alternatives that can never match.
+func (c *MetadataClient) lintPattern(p *cwpattern.Pattern) []lintFinding {
+    if p.Kind != cwpattern.JSONPattern {
+        return []lintFinding{{Pos: 0, Summary: "Not a JSON pattern", Detail: "LogStruct logs are JSON; use a { $.key = value } pattern to select them"}}
+    }
+    canonical := map[string]string{}
+    for name, key := range c.Keys { canonical[key] = name }
+    evtKey, srcKey := c.Keys["event"], c.Keys["source"]
+
+    var findings []lintFinding
+    add := func(n cwpattern.Node, summary, format string, args ...any) {
+        pos, _ := n.Span()
+        findings = append(findings, lintFinding{Pos: pos, Summary: summary, Detail: fmt.Sprintf(format, args...)})
+    }
+    cwpattern.Inspect(p.Expr, func(e cwpattern.Expr) bool {
+        var sel cwpattern.Selector
+        switch n := e.(type) {
+        case *cwpattern.Comparison:
+            sel = n.Selector
+        case *cwpattern.Check:
+            sel = n.Selector
+        default:
+            return true
+        }
+        key := sel.Key()
+        if key == "" {
+            add(sel, "No top-level key", "%s: LogStruct logs are JSON objects, so a selector must start with a key, e.g. $.key", sel.Text)
+            return true
+        }
+        name, ok := canonical[key]
+        if !ok {
+            add(sel, "Unknown key", "%s: LogStruct does not emit a key named %q", sel.Text, key)
+            return true
+        }
+        cmp, ok := e.(*cwpattern.Comparison)
+        if !ok || len(sel.Path) != 1 { return true }
+
+        v := cmp.Value
+        exact := v.Kind == cwpattern.String && !strings.Contains(v.Text, "*")
+        switch {
+        case exact && key == evtKey && len(c.structsAllowingEvent(v.Text)) == 0:
+            add(cmp, "Unknown event", "%s: no struct in the catalog emits event %q", p.Text(cmp), v.Text)
+        case exact && key == srcKey && len(c.StructsForSource(v.Text)) == 0:
+            add(cmp, "Unknown source", "%s: no struct in the catalog has source %q", p.Text(cmp), v.Text)
+        }
+        kind := c.KeyKind(name)
+        switch {
+        case kind == "" || kind == data.KindAny:
+        case kind == data.KindBoolean && v.Kind != cwpattern.Number:
+            add(cmp, "Mismatched type", "%s: %s holds boolean values, so comparing it with a string never matches; use %s IS TRUE or %s IS FALSE", p.Text(cmp), key, sel.Text, sel.Text)
+        case v.Kind == cwpattern.Number && !data.IsNumericKind(kind):
+            add(cmp, "Mismatched type", "%s: %s holds %s values, so comparing it with a number never matches", p.Text(cmp), key, kind)
+        case v.Kind != cwpattern.Number && data.IsNumericKind(kind):
+            add(cmp, "Mismatched type", "%s: %s holds %s values, so comparing it with a string never matches; remove the quotes", p.Text(cmp), key, kind)
+        }
+        return true
+    })
+
+    for _, branch := range lintBranches(p.Expr) {
+        if f, ok := c.lintBranch(p, branch); ok { findings = append(findings, f) }
+    }
+    sort.SliceStable(findings, func(i, j int) bool { return findings[i].Pos < findings[j].Pos })
+    return findings
+}
+
+// lintBranch reports a conjunction of clauses that no struct can satisfy:
+// conflicting events or sources, an event the source's structs never emit,
+// or a key none of the selected structs emits.
+func (c *MetadataClient) lintBranch(p *cwpattern.Pattern, branch []cwpattern.Expr) (lintFinding, bool) {
+    evtKey, srcKey := c.Keys["event"], c.Keys["source"]
+    values := map[string][]string{}
+    for _, e := range branch {
+        cmp, ok := e.(*cwpattern.Comparison)
+        if !ok || cmp.Op != "=" || cmp.Value.Kind != cwpattern.String || strings.Contains(cmp.Value.Text, "*") || len(cmp.Selector.Path) != 1 { continue }
+        if k := cmp.Selector.Key(); k == evtKey || k == srcKey {
+            if !contains(values[k], cmp.Value.Text) { values[k] = append(values[k], cmp.Value.Text) }
+        }
+    }
+    evts, srcs := values[evtKey], values[srcKey]
+    if len(evts) == 0 && len(srcs) == 0 { return lintFinding{}, false }
+
+    texts := make([]string, 0, len(branch))
+    for _, e := range branch { texts = append(texts, p.Text(e)) }
+    pos, _ := branch[0].Span()
+    impossible := func(format string, args ...any) (lintFinding, bool) {
+        detail := fmt.Sprintf("%s can never match: ", strings.Join(texts, " && ")) + fmt.Sprintf(format, args...)
+        return lintFinding{Pos: pos, Summary: "Impossible condition", Detail: detail}, true
+    }
+    if len(evts) > 1 { return impossible("%s cannot equal both %q and %q", evtKey, evts[0], evts[1]) }
+    if len(srcs) > 1 { return impossible("%s cannot equal both %q and %q", srcKey, srcs[0], srcs[1]) }
+
+    // structs that may emit the branch; sourceless structs carry any source
+    var candidates []string
+    for name, sc := range c.Structs {
+        if len(srcs) == 1 && sc.FixedSource != nil && *sc.FixedSource != srcs[0] { continue }
+        if len(evts) == 1 && !contains(sc.AllowedEvents, evts[0]) { continue }
+        candidates = append(candidates, name)
+    }
+    sort.Strings(candidates)
+    switch {
+    case len(evts) == 1 && len(c.structsAllowingEvent(evts[0])) == 0, len(srcs) == 1 && len(c.StructsForSource(srcs[0])) == 0:
+        // unknown values are reported on their own
+        return lintFinding{}, false
+    case len(candidates) == 0:
+        return impossible("no struct with source %q emits event %q", srcs[0], evts[0])
+    }
+
+    declared := map[string]bool{}
+    for _, sc := range c.Structs { for _, f := range sc.Fields { declared[f.Key] = true } }
+    for _, e := range branch {
+        var sel cwpattern.Selector
+        switch n := e.(type) {
+        case *cwpattern.Comparison:
+            sel = n.Selector
+        case *cwpattern.Check:
+            if n.Op == "NOT EXISTS" { continue }
+            sel = n.Selector
+        }
+        key := sel.Key()
+        if !declared[key] { continue }
+        emitted := false
+        for _, name := range candidates {
+            for _, f := range c.Structs[name].Fields { if f.Key == key { emitted = true } }
+        }
+        if !emitted { return impossible("%s is not emitted by %s", key, strings.Join(candidates, ", ")) }
+    }
+    return lintFinding{}, false
+}
+
+// structsAllowingEvent returns the structs that allow event.
+func (c *MetadataClient) structsAllowingEvent(event string) []string {
+    var names []string
+    for name, sc := range c.Structs {
+        if contains(sc.AllowedEvents, event) { names = append(names, name) }
+    }
+    return names
+}
+
+// lintBranches expands e into the conjunctions of clauses it is the
+// disjunction of, or nil when there are more than maxLintBranches.
+func lintBranches(e cwpattern.Expr) [][]cwpattern.Expr {
+    l, ok := e.(*cwpattern.Logical)
+    if !ok { return [][]cwpattern.Expr{{e}} }
+    xs, ys := lintBranches(l.X), lintBranches(l.Y)
+    if xs == nil || ys == nil { return nil }
+    if l.Op == "||" {
+        if len(xs)+len(ys) > maxLintBranches { return nil }
+        return append(xs, ys...)
+    }
+    if len(xs)*len(ys) > maxLintBranches { return nil }
+    var out [][]cwpattern.Expr
+    for _, x := range xs {
+        for _, y := range ys { out = append(out, append(append([]cwpattern.Expr(nil), x...), y...)) }
+    }
+    return out
+}
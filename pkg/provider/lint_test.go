@@ -0,0 +1,54 @@
+package provider
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/cwpattern"
+)
+
+func lint(t *testing.T, c *MetadataClient, pattern string) []lintFinding {
+    t.Helper()
+    p, err := cwpattern.Parse(pattern)
+    if err != nil { t.Fatalf("parse %s: %v", pattern, err) }
+    return c.lintPattern(p)
+}
+
+func TestLintPattern(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+
+    if got := lint(t, c, `{ $.evt = "delivered" && $.src = "mailer" && $.mailer_action = "welcome" }`); len(got) != 0 {
+        t.Errorf("expected no findings, got %v", got)
+    }
+    if got := lint(t, c, `{ $.exist IS TRUE }`); len(got) != 0 {
+        t.Errorf("expected no findings, got %v", got)
+    }
+    if got := lint(t, c, `{ ($.evt = "finish" || $.evt = "start") && $.src = "job" && $.duration_ms > 100 }`); len(got) != 0 {
+        t.Errorf("expected no findings, got %v", got)
+    }
+
+    cases := []struct {
+        pattern string
+        want    []string // findings, in order
+    }{
+        {`{ $.evt = "deliverd" }`, []string{`column 3: $.evt = "deliverd": no struct in the catalog emits event "deliverd"`}},
+        {`{ $.src = "mailr" }`, []string{`column 3: $.src = "mailr": no struct in the catalog has source "mailr"`}},
+        {`{ $.evtt = "delivered" }`, []string{`column 3: $.evtt: LogStruct does not emit a key named "evtt"`}},
+        {`{ $.status = "500" }`, []string{"column 3: " + `$.status = "500": status holds integer values, so comparing it with a string never matches; remove the quotes`}},
+        {`{ $.evt = "finish" && $.src = "mailer" }`, []string{`column 3: $.evt = "finish" && $.src = "mailer" can never match: no struct with source "mailer" emits event "finish"`}},
+        {`{ $.evt = "finish" && $.evt = "start" }`, []string{`column 3: $.evt = "finish" && $.evt = "start" can never match: evt cannot equal both "finish" and "start"`}},
+        {`{ $.src = "mailer" && $.queue_name = "default" }`, []string{"queue_name is not emitted by ActionMailer"}},
+        {`{ $.evt = "delivered" || ($.src = "job" && $.mailer_action = "x") }`, []string{`column 27: $.src = "job" && $.mailer_action = "x" can never match`}},
+        {`ERROR`, []string{"LogStruct logs are JSON"}},
+        {`{ $.exist = "true" }`, []string{`column 3: $.exist = "true": exist holds boolean values, so comparing it with a string never matches; use $.exist IS TRUE or $.exist IS FALSE`}},
+        {`{ $[0] = "x" }`, []string{`column 3: $[0]: LogStruct logs are JSON objects, so a selector must start with a key`}},
+    }
+    for _, tc := range cases {
+        got := lint(t, c, tc.pattern)
+        if len(got) != len(tc.want) { t.Errorf("%s: got %v, want %d findings", tc.pattern, got, len(tc.want)); continue }
+        for i, want := range tc.want {
+            if !strings.Contains(got[i].String(), want) { t.Errorf("%s: got %q, want %q", tc.pattern, got[i].String(), want) }
+        }
+    }
+}
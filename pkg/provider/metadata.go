@@ -2,18 +2,29 @@ package provider
 
 import (
     "fmt"
+    "sort"
     "github.com/DocSpring/terraform-provider-logstruct/pkg/data"
 )
 
 type MetadataClient struct {
     Keys    map[string]string
     Structs map[string]StructCatalog
+    // Version is the embedded LogStruct catalog version the client was built
+    // from, or empty when a configured catalog replaced it.
+    Version string
+    // Custom reports whether a configured catalog replaced or extended the embedded one.
+    Custom bool
 }
 
 type StructCatalog = data.StructCatalog
+type Field = data.Field
 
 func NewMetadataClient() (*MetadataClient, error) {
-    return &MetadataClient{Keys: data.CatalogData.Keys, Structs: data.CatalogData.Structs}, nil
+    return NewMetadataClientFromCatalog(data.LatestVersion(), data.CatalogData), nil
+}
+
+func NewMetadataClientFromCatalog(version string, cat data.Catalog) *MetadataClient {
+    return &MetadataClient{Keys: cat.Keys, Structs: cat.Structs, Version: version}
 }
 
 func (c *MetadataClient) AllowedEventsForStruct(structName string) ([]string, bool, error) {
@@ -30,3 +41,48 @@ func (c *MetadataClient) FixedSourceForStruct(structName string) (string, bool,
     if si.FixedSource != nil { return *si.FixedSource, true, nil }
     return "", false, nil
 }
+
+func (c *MetadataClient) FieldsForStruct(structName string) ([]Field, error) {
+    si, ok := c.Structs[structName]
+    if !ok { return nil, fmt.Errorf("unknown struct: %s", structName) }
+    return si.Fields, nil
+}
+
+// StructsForSource returns the sorted names of structs whose fixed source is src.
+func (c *MetadataClient) StructsForSource(src string) []string {
+    var names []string
+    for name, sc := range c.Structs {
+        if sc.FixedSource != nil && *sc.FixedSource == src { names = append(names, name) }
+    }
+    sort.Strings(names)
+    return names
+}
+
+// SerializedKey returns the JSON key a canonical key name serializes to.
+func (c *MetadataClient) SerializedKey(canonical string) (string, error) {
+    key, ok := c.Keys[canonical]
+    if !ok { return "", fmt.Errorf("unknown key: %s", canonical) }
+    return key, nil
+}
+
+// KeyKind returns the kind of the values a canonical key holds, from the
+// fields that declare it: their shared kind, float when they are numeric but
+// disagree, and any when they disagree otherwise. Keys no struct declares have
+// no kind.
+func (c *MetadataClient) KeyKind(canonical string) string {
+    kind := ""
+    for _, sc := range c.Structs {
+        for _, f := range sc.Fields {
+            if f.Name != canonical || f.Kind == "" || f.Kind == kind { continue }
+            switch {
+            case kind == "":
+                kind = f.Kind
+            case data.IsNumericKind(kind) && f.Numeric():
+                kind = data.KindFloat
+            default:
+                return data.KindAny
+            }
+        }
+    }
+    return kind
+}
@@ -20,3 +20,52 @@ func TestMetadataClient_Basics(t *testing.T) {
     }
 }
 
+
+func TestMetadataClient_Fields(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+
+    has := func(structName, field string) bool {
+        fields, err := c.FieldsForStruct(structName)
+        if err != nil { t.Fatalf("fields: %v", err) }
+        for _, f := range fields { if f.Name == field { return true } }
+        return false
+    }
+    if !has("ActionMailer", "mailer_action") { t.Errorf("expected ActionMailer to have mailer_action") }
+    if has("Puma", "mailer_action") { t.Errorf("did not expect Puma to have mailer_action") }
+
+    // Every field serializes to the key the global map declares
+    for name, sc := range c.Structs {
+        for _, f := range sc.Fields {
+            if c.Keys[f.Name] != f.Key { t.Errorf("%s.%s: key %q, catalog has %q", name, f.Name, f.Key, c.Keys[f.Name]) }
+        }
+    }
+    if _, err := c.FieldsForStruct("Nope"); err == nil { t.Errorf("expected unknown struct error") }
+}
+
+func TestMetadataClient_StructsForSource(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+
+    got := c.StructsForSource("app")
+    want := []string{"Ahoy", "Plain", "SQL"}
+    if len(got) != len(want) { t.Fatalf("got %v, want %v", got, want) }
+    for i := range want { if got[i] != want[i] { t.Fatalf("got %v, want %v", got, want) } }
+    if len(c.StructsForSource("nope")) != 0 { t.Errorf("expected no structs for unknown source") }
+}
+
+func TestMetadataClient_KeyKind(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+    cases := map[string]string{
+        "duration_ms": "float",
+        "status":      "integer",
+        "event":       "string",
+        "timestamp":   "timestamp",
+        "message":     "any", // String in some structs, untyped in others
+        "retry_count": "",    // declared by no struct
+    }
+    for key, want := range cases {
+        if got := c.KeyKind(key); got != want { t.Errorf("%s: got %q, want %q", key, got, want) }
+    }
+}
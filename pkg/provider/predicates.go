@@ -0,0 +1,145 @@
+package provider
+
+import (
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/data"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+    opEqual        = "="
+    opNotEqual     = "!="
+    opGreater      = ">"
+    opGreaterEqual = ">="
+    opLess         = "<"
+    opLessEqual    = "<="
+)
+
+// numberLiteral and integerLiteral match the decimal number literals every
+// dialect accepts; strconv also takes NaN, Inf, hex floats and underscores.
+var (
+    numberLiteral  = regexp.MustCompile(`^-?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+    integerLiteral = regexp.MustCompile(`^-?\d+$`)
+)
+
+var predicateOperators = []string{opEqual, opNotEqual, opGreater, opGreaterEqual, opLess, opLessEqual}
+
+func isNumericOperator(op string) bool {
+    switch op {
+    case opGreater, opGreaterEqual, opLess, opLessEqual:
+        return true
+    }
+    return false
+}
+
+type predicateModel struct {
+    Key      types.String   `tfsdk:"key"`
+    Operator types.String   `tfsdk:"operator"`
+    Value    types.String   `tfsdk:"value"`
+    Values   []types.String `tfsdk:"values"`
+}
+
+func predicatesBlock() schema.ListNestedBlock {
+    return schema.ListNestedBlock{
+        Description: "Additional clauses ANDed onto the pattern",
+        NestedObject: schema.NestedBlockObject{
+            Attributes: map[string]schema.Attribute{
+                "key":      schema.StringAttribute{Required: true, Description: "Canonical key name from the catalog (e.g., mailer_action, http_method, queue_name)"},
+                "operator": schema.StringAttribute{Optional: true, Description: "One of " + strings.Join(predicateOperators, ", ") + " (default =)"},
+                "value":    schema.StringAttribute{Optional: true, Description: "Value to compare with"},
+                "values":   schema.ListAttribute{Optional: true, ElementType: types.StringType, Description: "Values to compare with; ORed for =, ANDed for !="},
+            },
+        },
+    }
+}
+
+// predicate validates a comparison on a canonical key against the catalog and
+// compiles it. Several values for = are ORed; several values for != are ANDed.
+func (c *MetadataClient) predicate(canonical, op string, values []string) (expr, error) {
+    key, ok := c.Keys[canonical]
+    if !ok { return nil, fmt.Errorf("unknown key %q; expected a canonical key from the catalog", canonical) }
+    if op == "" { op = opEqual }
+    valid := false
+    for _, o := range predicateOperators { if o == op { valid = true; break } }
+    if !valid { return nil, fmt.Errorf("unsupported operator %q; expected one of %s", op, strings.Join(predicateOperators, ", ")) }
+    if len(values) == 0 { return nil, fmt.Errorf("at least one value is required for key %q", canonical) }
+
+    // keys no struct declares, and untyped ones, are compared as the
+    // operator implies
+    kind := c.KeyKind(canonical)
+    // a quoted value never equals a JSON array or object
+    if kind == data.KindArray || kind == data.KindObject {
+        return nil, fmt.Errorf("%q holds %s values, which predicates cannot compare", canonical, kind)
+    }
+    if isNumericOperator(op) {
+        if kind != "" && kind != data.KindAny && !data.IsNumericKind(kind) {
+            return nil, fmt.Errorf("operator %s requires a numeric key, but %q holds %s values", op, canonical, kind)
+        }
+        if len(values) != 1 { return nil, fmt.Errorf("operator %s takes exactly one value", op) }
+        if !numberLiteral.MatchString(values[0]) {
+            return nil, fmt.Errorf("operator %s requires a numeric value, got %q", op, values[0])
+        }
+        return cmpExpr{Key: key, Op: op, Value: values[0], Numeric: true}, nil
+    }
+
+    numeric, boolean := data.IsNumericKind(kind), kind == data.KindBoolean
+    cmps := make([]expr, 0, len(values))
+    for _, v := range values {
+        if numeric {
+            if err := checkNumber(kind, v); err != nil { return nil, fmt.Errorf("%q holds %s values: %v", canonical, kind, err) }
+        }
+        if boolean && v != "true" && v != "false" {
+            return nil, fmt.Errorf("%q holds boolean values: value %q is not true or false", canonical, v)
+        }
+        cmps = append(cmps, cmpExpr{Key: key, Op: op, Value: v, Numeric: numeric, Boolean: boolean})
+    }
+    if len(cmps) == 1 { return cmps[0], nil }
+    if op == opNotEqual { return allExpr(cmps), nil }
+    return anyExpr(cmps), nil
+}
+
+// checkNumber validates v as a literal of a numeric kind.
+func checkNumber(kind, v string) error {
+    if kind == data.KindInteger {
+        if _, err := strconv.ParseInt(v, 10, 64); err != nil || !integerLiteral.MatchString(v) { return fmt.Errorf("value %q is not an integer", v) }
+        return nil
+    }
+    if !numberLiteral.MatchString(v) { return fmt.Errorf("value %q is not a number", v) }
+    return nil
+}
+
+// predicateExprs compiles predicate blocks, reporting errors against the
+// offending block under base.
+func (c *MetadataClient) predicateExprs(preds []predicateModel, base path.Path) ([]expr, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    var out []expr
+    for i, p := range preds {
+        at := base.AtListIndex(i)
+        var values []string
+        if !p.Value.IsNull() { values = append(values, p.Value.ValueString()) }
+        for _, v := range p.Values { values = append(values, v.ValueString()) }
+        if !p.Value.IsNull() && len(p.Values) > 0 {
+            diags.AddAttributeError(at, "Invalid predicate", "set either value or values, not both")
+            continue
+        }
+        if _, ok := c.Keys[p.Key.ValueString()]; !ok {
+            diags.AddAttributeError(at.AtName("key"), "Unknown predicate key",
+                fmt.Sprintf("key %q is not a canonical key in the catalog", p.Key.ValueString()))
+            continue
+        }
+        e, err := c.predicate(p.Key.ValueString(), p.Operator.ValueString(), values)
+        if err != nil {
+            diags.AddAttributeError(at, "Invalid predicate", err.Error())
+            continue
+        }
+        out = append(out, e)
+    }
+    return out, diags
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestPredicate_Compile(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+
+    cases := []struct {
+        canonical string
+        op        string
+        values    []string
+        want      string
+    }{
+        {"mailer_action", "", []string{"welcome"}, `{ $.mailer_action = "welcome" }`},
+        {"http_method", "=", []string{"GET", "POST"}, `{ ($.method = "GET" || $.method = "POST") }`},
+        {"queue_name", "!=", []string{"low", "default"}, `{ $.queue_name != "low" && $.queue_name != "default" }`},
+        {"duration_ms", ">", []string{"500"}, `{ $.duration_ms > 500 }`},
+        {"path", "=", []string{`a"b`}, `{ $.path = "a\"b" }`},
+        {"status", "=", []string{"500", "503"}, `{ ($.status = 500 || $.status = 503) }`},
+        {"duration_ms", "!=", []string{"0.5"}, `{ $.duration_ms != 0.5 }`},
+        {"exist", "=", []string{"true"}, `{ $.exist IS TRUE }`},
+        {"exist", "=", []string{"false"}, `{ $.exist IS FALSE }`},
+        {"exist", "!=", []string{"true"}, `{ $.exist IS FALSE }`},
+        {"exist", "=", []string{"true", "false"}, `{ ($.exist IS TRUE || $.exist IS FALSE) }`},
+        // timestamps serialize as strings and compare as such
+        {"started_at", "=", []string{"2024-01-01T00:00:00Z"}, `{ $.started_at = "2024-01-01T00:00:00Z" }`},
+    }
+    for _, tc := range cases {
+        e, err := c.predicate(tc.canonical, tc.op, tc.values)
+        if err != nil { t.Fatalf("%s: %v", tc.canonical, err) }
+        if got := renderCloudWatch(allExpr{}.and(e)); got != tc.want {
+            t.Errorf("%s: got %s, want %s", tc.canonical, got, tc.want)
+        }
+    }
+}
+
+func TestPredicate_Errors(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+
+    if _, err := c.predicate("no_such_key", "=", []string{"x"}); err == nil { t.Errorf("expected unknown key error") }
+    if _, err := c.predicate("status", "~", []string{"x"}); err == nil { t.Errorf("expected operator error") }
+    if _, err := c.predicate("status", "=", nil); err == nil { t.Errorf("expected missing value error") }
+    if _, err := c.predicate("duration_ms", ">", []string{"slow"}); err == nil { t.Errorf("expected numeric value error") }
+    if _, err := c.predicate("duration_ms", ">", []string{"1", "2"}); err == nil { t.Errorf("expected single value error") }
+    if _, err := c.predicate("queue_name", ">=", []string{"5"}); err == nil { t.Errorf("expected numeric key error") }
+    if _, err := c.predicate("status", "=", []string{"ok"}); err == nil { t.Errorf("expected integer value error") }
+    if _, err := c.predicate("status", "=", []string{"2.5"}); err == nil { t.Errorf("expected integer value error") }
+    for _, v := range []string{"yes", "1", "TRUE", ""} {
+        if _, err := c.predicate("exist", "=", []string{v}); err == nil || !strings.Contains(err.Error(), "not true or false") { t.Errorf("exist = %q: expected boolean value error, got %v", v, err) }
+    }
+    if _, err := c.predicate("exist", ">", []string{"1"}); err == nil { t.Errorf("expected numeric key error for a boolean") }
+    // a quoted value never equals a JSON array or object
+    for _, key := range []string{"to", "context"} {
+        for _, op := range []string{"=", "!="} {
+            if _, err := c.predicate(key, op, []string{"x"}); err == nil || !strings.Contains(err.Error(), "which predicates cannot compare") { t.Errorf("%s %s x: expected kind error, got %v", key, op, err) }
+        }
+    }
+    for _, v := range []string{"NaN", "Inf", "-Inf", "0x1p3", "1_000", "+5"} {
+        if _, err := c.predicate("duration_ms", ">", []string{v}); err == nil { t.Errorf("%s: expected numeric value error", v) }
+        if _, err := c.predicate("status", "=", []string{v}); err == nil { t.Errorf("%s: expected integer value error", v) }
+    }
+    for _, v := range []string{"5", "-1.5", ".5", "1e3"} {
+        if _, err := c.predicate("duration_ms", ">", []string{v}); err != nil { t.Errorf("%s: %v", v, err) }
+    }
+}
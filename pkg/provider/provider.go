@@ -2,23 +2,40 @@ package provider
 
 import (
     "context"
+    "fmt"
+    "os"
 
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/data"
     "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/function"
     fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+    "github.com/hashicorp/terraform-plugin-framework/path"
     "github.com/hashicorp/terraform-plugin-framework/provider/schema"
     "github.com/hashicorp/terraform-plugin-framework/schema/validator"
     "github.com/hashicorp/terraform-plugin-framework/types"
     "github.com/hashicorp/terraform-plugin-framework/resource"
 )
 
+var _ fwprovider.ProviderWithFunctions = &logstructProvider{}
+
 type logstructProvider struct {
     version string
 }
 
 type providerModel struct {
-    ExportDir types.String `tfsdk:"export_dir"`
+    ExportDir   types.String `tfsdk:"export_dir"`
+    CatalogPath types.String `tfsdk:"catalog_path"`
+    CatalogJSON types.String `tfsdk:"catalog_json"`
+    CatalogMode types.String `tfsdk:"catalog_mode"`
+    Version     types.String `tfsdk:"logstruct_version"`
 }
 
+const (
+    catalogModeReplace = "replace"
+    catalogModeMerge   = "merge"
+)
+
 func New(version string) func() fwprovider.Provider {
     return func() fwprovider.Provider {
         return &logstructProvider{version: version}
@@ -34,6 +51,10 @@ func (p *logstructProvider) Schema(_ context.Context, _ fwprovider.SchemaRequest
     resp.Schema = schema.Schema{
         Attributes: map[string]schema.Attribute{
             "export_dir": schema.StringAttribute{Optional: true, Validators: []validator.String{}},
+            "catalog_path": schema.StringAttribute{Optional: true, Description: "Path to a catalog JSON file in the schema of the embedded catalogs"},
+            "catalog_json": schema.StringAttribute{Optional: true, Description: "Inline catalog JSON in the schema of the embedded catalogs"},
+            "catalog_mode": schema.StringAttribute{Optional: true, Description: "How a configured catalog combines with the embedded one: replace (default) or merge"},
+            "logstruct_version": schema.StringAttribute{Optional: true, Description: "LogStruct gem version whose embedded catalog to use (default: latest)"},
         },
     }
 }
@@ -45,21 +66,155 @@ func (p *logstructProvider) Configure(ctx context.Context, req fwprovider.Config
     if resp.Diagnostics.HasError() {
         return
     }
-    client, err := NewMetadataClient()
-    if err != nil {
-        resp.Diagnostics.AddError("Metadata Load Error", err.Error())
+    client, diags := configuredClient(cfg)
+    resp.Diagnostics.Append(diags...)
+    if resp.Diagnostics.HasError() {
         return
     }
     resp.DataSourceData = client
 }
 
+// configuredClient builds the metadata client for the provider configuration.
+// A replaced catalog did not come from an embedded LogStruct version, so the
+// client reports no version for it.
+func configuredClient(cfg providerModel) (*MetadataClient, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    if cfg.Version.IsUnknown() {
+        diags.AddAttributeError(path.Root("logstruct_version"), "Unknown LogStruct version", "logstruct_version must be known when the provider is configured")
+        return nil, diags
+    }
+    version := cfg.Version.ValueString()
+    if version == "" {
+        version = data.LatestVersion()
+    }
+    base, err := data.CatalogForVersion(version)
+    if err != nil {
+        diags.AddAttributeError(path.Root("logstruct_version"), "Metadata Load Error", err.Error())
+        return nil, diags
+    }
+    cat, ok, d := loadCatalog(cfg, base)
+    diags.Append(d...)
+    if diags.HasError() {
+        return nil, diags
+    }
+    if !ok {
+        return NewMetadataClientFromCatalog(version, base), diags
+    }
+    if cfg.CatalogMode.ValueString() != catalogModeMerge {
+        version = ""
+    }
+    client := NewMetadataClientFromCatalog(version, cat)
+    client.Custom = true
+    return client, diags
+}
+
 func (p *logstructProvider) DataSources(context.Context) []func() datasource.DataSource {
     return []func() datasource.DataSource{
         NewSourceDataSource,
+        NewStructDataSource,
         NewPatternDataSource,
+        NewCloudWatchFilterDataSource,
+        NewCatalogDataSource,
+        NewInsightsQueryDataSource,
+        NewDatadogQueryDataSource,
+        NewLokiQueryDataSource,
+        NewOpenSearchQueryDataSource,
+        NewSplunkSearchDataSource,
+        NewGCPLoggingFilterDataSource,
+        NewKQLQueryDataSource,
+        NewNRQLDataSource,
+        NewVectorConditionDataSource,
+        NewFluentBitGrepDataSource,
+        NewQueryDataSource,
+        NewPatternLintDataSource,
+    }
+}
+
+// Functions read the embedded catalog; Terraform does not pass provider
+// configuration to provider functions.
+func (p *logstructProvider) Functions(context.Context) []func() function.Function {
+    return []func() function.Function{
+        NewPatternFunction,
+        NewKeyFunction,
+        NewValidEventFunction,
+        NewMatchesFunction,
     }
 }
 
 func (p *logstructProvider) Resources(context.Context) []func() resource.Resource {
     return []func() resource.Resource{}
 }
+
+// loadCatalog reads the catalog configured through catalog_path or
+// catalog_json, combines it with the embedded base catalog according to
+// catalog_mode and validates the result, reporting every problem. It reports
+// false when no catalog is configured.
+func loadCatalog(cfg providerModel, base data.Catalog) (data.Catalog, bool, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    for _, a := range []struct {
+        name string
+        v    types.String
+    }{{"catalog_path", cfg.CatalogPath}, {"catalog_json", cfg.CatalogJSON}, {"catalog_mode", cfg.CatalogMode}} {
+        if a.v.IsUnknown() {
+            diags.AddAttributeError(path.Root(a.name), "Unknown catalog configuration", a.name+" must be known when the provider is configured")
+        }
+    }
+    if diags.HasError() {
+        return data.Catalog{}, false, diags
+    }
+
+    mode := cfg.CatalogMode.ValueString()
+    if mode == "" {
+        mode = catalogModeReplace
+    }
+    if mode != catalogModeReplace && mode != catalogModeMerge {
+        diags.AddAttributeError(path.Root("catalog_mode"), "Invalid catalog mode", "catalog_mode must be replace or merge, got "+mode)
+        return data.Catalog{}, false, diags
+    }
+
+    var raw []byte
+    var at path.Path
+    var origin string
+    switch {
+    case !cfg.CatalogPath.IsNull() && !cfg.CatalogJSON.IsNull():
+        diags.AddAttributeError(path.Root("catalog_json"), "Conflicting catalog configuration", "set either catalog_path or catalog_json, not both")
+        return data.Catalog{}, false, diags
+    case !cfg.CatalogPath.IsNull():
+        at, origin = path.Root("catalog_path"), cfg.CatalogPath.ValueString()
+        b, err := os.ReadFile(origin)
+        if err != nil {
+            diags.AddAttributeError(at, "Catalog Load Error", err.Error())
+            return data.Catalog{}, false, diags
+        }
+        raw = b
+    case !cfg.CatalogJSON.IsNull():
+        at, origin = path.Root("catalog_json"), "catalog_json"
+        raw = []byte(cfg.CatalogJSON.ValueString())
+    default:
+        if !cfg.CatalogMode.IsNull() {
+            diags.AddAttributeWarning(path.Root("catalog_mode"), "Unused catalog mode", "catalog_mode has no effect without catalog_path or catalog_json")
+        }
+        return data.Catalog{}, false, diags
+    }
+
+    cat, err := data.ParseCatalog(raw)
+    if err != nil {
+        diags.AddAttributeError(at, "Malformed catalog", fmt.Sprintf("%s: %v", origin, err))
+        return data.Catalog{}, false, diags
+    }
+    if mode == catalogModeMerge {
+        cat = base.Merge(cat)
+    } else if cat.Keys == nil || cat.Structs == nil {
+        diags.AddAttributeError(at, "Malformed catalog", origin+": a replacement catalog needs both keys and structs; set catalog_mode = \"merge\" to extend the embedded catalog instead")
+        return data.Catalog{}, false, diags
+    }
+    // check the whole catalog once, rather than each data source tripping
+    // over the same problem at read time
+    for _, err := range cat.Validate() {
+        diags.AddAttributeError(at, "Invalid catalog", fmt.Sprintf("%s: %v", origin, err))
+    }
+    if diags.HasError() {
+        return data.Catalog{}, false, diags
+    }
+    return cat, true, diags
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/data"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLoadCatalog(t *testing.T) {
+    // nothing configured: embedded catalog
+    if _, ok, diags := loadCatalog(providerModel{}, data.CatalogData); ok || diags.HasError() { t.Fatalf("expected no catalog, got %v", diags) }
+
+    file := filepath.Join(t.TempDir(), "catalog.json")
+    full := `{"keys": {"event": "evt", "source": "src", "level": "lvl", "timestamp": "ts"}, "structs": {"Custom": {"name": "Custom", "fixed_source": "custom", "allowed_events": ["log"]}}}`
+    if err := os.WriteFile(file, []byte(full), 0o600); err != nil { t.Fatalf("write: %v", err) }
+    cat, ok, diags := loadCatalog(providerModel{CatalogPath: types.StringValue(file)}, data.CatalogData)
+    if !ok || diags.HasError() { t.Fatalf("catalog_path: %v", diags) }
+    if len(cat.Structs) != 1 { t.Errorf("expected replaced catalog, got %d structs", len(cat.Structs)) }
+
+    cat, ok, diags = loadCatalog(providerModel{CatalogJSON: types.StringValue(`{"structs": {"Custom": {"name": "Custom", "allowed_events": ["log"]}}}`), CatalogMode: types.StringValue("merge")}, data.CatalogData)
+    if !ok || diags.HasError() { t.Fatalf("catalog_json merge: %v", diags) }
+    if _, ok := cat.Structs["Custom"]; !ok { t.Errorf("expected merged struct") }
+    if _, ok := cat.Structs["ActionMailer"]; !ok { t.Errorf("expected embedded struct after merge") }
+
+    failures := map[string]providerModel{
+        "line 1":                            {CatalogJSON: types.StringValue(`{"keys": }`)},
+        "needs both":                        {CatalogJSON: types.StringValue(`{"keys": {}}`)},
+        "not both":                          {CatalogJSON: types.StringValue(full), CatalogPath: types.StringValue(file)},
+        "replace or merge":                  {CatalogJSON: types.StringValue(full), CatalogMode: types.StringValue("append")},
+        "no such file":                      {CatalogPath: types.StringValue(filepath.Join(t.TempDir(), "missing.json"))},
+        "must be known":                     {CatalogJSON: types.StringUnknown()},
+        "missing required key \"level\"":    {CatalogJSON: types.StringValue(`{"keys": {"event": "evt", "source": "src"}, "structs": {}}`)},
+        "structs.Custom: no allowed_events": {CatalogJSON: types.StringValue(`{"structs": {"Custom": {"name": "Custom"}}}`), CatalogMode: types.StringValue("merge")},
+    }
+    for want, cfg := range failures {
+        _, _, diags := loadCatalog(cfg, data.CatalogData)
+        if !diags.HasError() { t.Errorf("%s: expected error", want); continue }
+        if d := diags.Errors()[0].Detail(); !strings.Contains(d, want) { t.Errorf("got %q, want it to contain %q", d, want) }
+    }
+
+    // every problem is reported, not just the first
+    _, _, diags = loadCatalog(providerModel{CatalogJSON: types.StringValue(`{"keys": {"event": "evt"}, "structs": {"A": {"name": "B", "allowed_events": ["x"]}}}`)}, data.CatalogData)
+    if n := diags.ErrorsCount(); n != 4 { t.Errorf("expected 4 errors, got %d: %v", n, diags) }
+}
+
+func TestConfiguredClient_Version(t *testing.T) {
+    client, diags := configuredClient(providerModel{})
+    if diags.HasError() { t.Fatalf("embedded: %v", diags) }
+    if client.Version != data.LatestVersion() || client.Custom { t.Errorf("embedded: got version %q, custom %v", client.Version, client.Custom) }
+
+    full := `{"keys": {"event": "evt", "source": "src", "level": "lvl", "timestamp": "ts"}, "structs": {"Custom": {"name": "Custom", "fixed_source": "custom", "allowed_events": ["log"]}}}`
+    client, diags = configuredClient(providerModel{CatalogJSON: types.StringValue(full), Version: types.StringValue(data.LatestVersion())})
+    if diags.HasError() { t.Fatalf("replace: %v", diags) }
+    if client.Version != "" || !client.Custom { t.Errorf("replace: got version %q, custom %v", client.Version, client.Custom) }
+
+    client, diags = configuredClient(providerModel{CatalogJSON: types.StringValue(`{"structs": {}}`), CatalogMode: types.StringValue("merge")})
+    if diags.HasError() { t.Fatalf("merge: %v", diags) }
+    if client.Version != data.LatestVersion() || !client.Custom { t.Errorf("merge: got version %q, custom %v", client.Version, client.Custom) }
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+    "fmt"
+    "strings"
+)
+
+// renderDatadog renders e as a Datadog log search query. Attributes are
+// addressed as @<serialized key>; top-level clauses are joined by spaces,
+// which Datadog treats as AND. A negated term also matches logs without the
+// attribute, so != comparisons are guarded by an @key:* term.
+func renderDatadog(e expr) string {
+    e = requirePresent(e, notEqual)
+    if all, ok := e.(allExpr); ok {
+        parts := make([]string, 0, len(all))
+        for _, c := range all { parts = append(parts, datadogExpr(c, true)) }
+        return strings.Join(parts, " ")
+    }
+    return datadogExpr(e, false)
+}
+
+func datadogExpr(e expr, nested bool) string {
+    switch n := e.(type) {
+    case allExpr:
+        return datadogJoin(n, " AND ", nested)
+    case anyExpr:
+        return datadogJoin(n, " OR ", nested)
+    case cmpExpr:
+        switch n.Op {
+        case opEqual:
+            return "@" + n.Key + ":" + datadogValue(n.Value)
+        case opNotEqual:
+            return "-@" + n.Key + ":" + datadogValue(n.Value)
+        }
+        return "@" + n.Key + ":" + n.Op + n.Value
+    case presentExpr:
+        return "@" + n.Key + ":*"
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func datadogJoin(children []expr, sep string, nested bool) string {
+    parts := make([]string, 0, len(children))
+    for _, c := range children { parts = append(parts, datadogExpr(c, true)) }
+    s := strings.Join(parts, sep)
+    if nested && len(parts) > 1 { return "(" + s + ")" }
+    return s
+}
+
+// datadogValue leaves plain words bare and double-quotes anything containing
+// characters the search syntax reserves, as well as the AND/OR/NOT operators.
+func datadogValue(v string) string {
+    switch v {
+    case "", "AND", "OR", "NOT":
+        return quoteDouble(v)
+    }
+    for _, r := range v {
+        if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.') {
+            return quoteDouble(v)
+        }
+    }
+    return v
+}
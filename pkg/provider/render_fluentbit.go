@@ -0,0 +1,86 @@
+package provider
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+)
+
+// fluentBitRule is one rule of a Fluent Bit grep filter: Regex keeps records
+// whose key matches the pattern, Exclude drops them.
+type fluentBitRule struct {
+    Type    string
+    Key     string
+    Pattern string
+}
+
+func (r fluentBitRule) String() string { return r.Key + " " + r.Pattern }
+
+// fluentBitGrep is a Fluent Bit grep filter. A record is kept when every
+// Regex rule matches and no Exclude rule does.
+type fluentBitGrep struct {
+    Match string
+    // RecordKey, when set, is the record key holding the parsed LogStruct
+    // map; rule keys become record accessors into it.
+    RecordKey string
+    Rules     []fluentBitRule
+}
+
+// rules compiles e into grep rules. Grep cannot express numeric
+// comparisons or alternatives across different keys, so those are errors.
+// An Exclude rule keeps records without the key, so != comparisons are
+// paired with a Regex rule requiring it.
+func (g fluentBitGrep) rules(e expr) ([]fluentBitRule, error) {
+    e = requirePresent(e, notEqual)
+    clauses := []expr{e}
+    if all, ok := e.(allExpr); ok { clauses = all }
+    var rules []fluentBitRule
+    for _, c := range clauses {
+        switch n := c.(type) {
+        case cmpExpr:
+            if isNumericOperator(n.Op) { return nil, fmt.Errorf("the grep filter cannot compare %s %s %s numerically", n.Key, n.Op, n.Value) }
+            if n.Boolean { return nil, fmt.Errorf("the grep filter only matches strings, so it cannot compare the boolean %s", n.Key) }
+            typ := "Regex"
+            if n.Op == opNotEqual { typ = "Exclude" }
+            rules = append(rules, fluentBitRule{Type: typ, Key: g.key(n.Key), Pattern: "^" + regexp.QuoteMeta(n.Value) + "$"})
+        case anyExpr:
+            key, values, ok := sameKeyEquals(n)
+            if !ok { return nil, fmt.Errorf("the grep filter can only OR equality comparisons on a single key") }
+            if n[0].(cmpExpr).Boolean { return nil, fmt.Errorf("the grep filter only matches strings, so it cannot compare the boolean %s", key) }
+            quoted := make([]string, 0, len(values))
+            for _, v := range values { quoted = append(quoted, regexp.QuoteMeta(v)) }
+            rules = append(rules, fluentBitRule{Type: "Regex", Key: g.key(key), Pattern: "^(" + strings.Join(quoted, "|") + ")$"})
+        case presentExpr:
+            rules = append(rules, fluentBitRule{Type: "Regex", Key: g.key(n.Key), Pattern: "^.*$"})
+        default:
+            return nil, fmt.Errorf("the grep filter cannot express nested conditions")
+        }
+    }
+    return rules, nil
+}
+
+func (g fluentBitGrep) key(key string) string {
+    if g.RecordKey == "" { return key }
+    return "$" + g.RecordKey + "['" + key + "']"
+}
+
+// String renders the filter in the classic configuration format.
+func (g fluentBitGrep) String() string {
+    lines := []string{"[FILTER]", "    Name    grep", "    Match   " + g.Match}
+    for _, r := range g.Rules { lines = append(lines, fmt.Sprintf("    %-7s %s", r.Type, r)) }
+    return strings.Join(lines, "\n") + "\n"
+}
+
+// sameKeyEquals reports whether alts are all equality comparisons on one
+// key, returning the key and values.
+func sameKeyEquals(alts anyExpr) (string, []string, bool) {
+    var key string
+    var values []string
+    for i, a := range alts {
+        c, ok := a.(cmpExpr)
+        if !ok || c.Op != opEqual || i > 0 && c.Key != key { return "", nil, false }
+        key = c.Key
+        values = append(values, c.Value)
+    }
+    return key, values, len(values) > 0
+}
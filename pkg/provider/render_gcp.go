@@ -0,0 +1,89 @@
+package provider
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// gcpSeverities maps LogStruct level values to Cloud Logging severities.
+var gcpSeverities = map[string]string{
+    "debug":   "DEBUG",
+    "info":    "INFO",
+    "warn":    "WARNING",
+    "error":   "ERROR",
+    "fatal":   "CRITICAL",
+    "unknown": "DEFAULT",
+}
+
+// gcpSeverity returns the Cloud Logging severity for a LogStruct level.
+func gcpSeverity(level string) (string, error) {
+    sev, ok := gcpSeverities[level]
+    if !ok {
+        levels := make([]string, 0, len(gcpSeverities))
+        for l := range gcpSeverities { levels = append(levels, l) }
+        sort.Strings(levels)
+        return "", fmt.Errorf("unknown level %q; expected one of %s", level, strings.Join(levels, ", "))
+    }
+    return sev, nil
+}
+
+// gcpFilter renders expressions in the Cloud Logging query language.
+type gcpFilter struct {
+    Payload string // payload field holding the JSON, normally jsonPayload
+    // LevelKey is the serialized level key; comparisons on it are rendered
+    // against severity when set.
+    LevelKey string
+    // MinSeverity restricts matches to entries at or above this severity.
+    MinSeverity string
+}
+
+// render renders e as a filter. A != comparison also matches entries
+// without the field, so it is guarded by a :* presence test unless the
+// field is mapped to severity, which every entry has.
+func (g gcpFilter) render(e expr) (string, error) {
+    e = requirePresent(e, func(c cmpExpr) bool { return c.Op == opNotEqual && (g.LevelKey == "" || c.Key != g.LevelKey) })
+    var out string
+    var err error
+    if all, ok := e.(allExpr); ok {
+        out, err = g.join(all, " AND ", false)
+    } else {
+        out, err = g.expr(e, false)
+    }
+    if err != nil || g.MinSeverity == "" { return out, err }
+    if out == "" { return "severity>=" + g.MinSeverity, nil }
+    return out + " AND severity>=" + g.MinSeverity, nil
+}
+
+func (g gcpFilter) expr(e expr, nested bool) (string, error) {
+    switch n := e.(type) {
+    case allExpr:
+        return g.join(n, " AND ", nested)
+    case anyExpr:
+        return g.join(n, " OR ", nested)
+    case cmpExpr:
+        if g.LevelKey != "" && n.Key == g.LevelKey {
+            sev, err := gcpSeverity(n.Value)
+            if err != nil { return "", err }
+            return "severity" + n.Op + sev, nil
+        }
+        lit := quoteDouble(n.Value)
+        if n.Numeric || n.Boolean { lit = n.Value }
+        return g.Payload + "." + n.Key + n.Op + lit, nil
+    case presentExpr:
+        return g.Payload + "." + n.Key + ":*", nil
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func (g gcpFilter) join(children []expr, sep string, nested bool) (string, error) {
+    parts := make([]string, 0, len(children))
+    for _, c := range children {
+        s, err := g.expr(c, true)
+        if err != nil { return "", err }
+        parts = append(parts, s)
+    }
+    s := strings.Join(parts, sep)
+    if nested && len(parts) > 1 { return "(" + s + ")", nil }
+    return s, nil
+}
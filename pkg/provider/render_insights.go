@@ -0,0 +1,51 @@
+package provider
+
+import (
+    "fmt"
+    "strings"
+)
+
+// insightsQuery is a CloudWatch Logs Insights query. Field names are
+// serialized keys or Insights system fields such as @timestamp.
+type insightsQuery struct {
+    Fields    []string
+    Filter    expr
+    Stats     string // aggregation and grouping, without the stats command
+    SortBy    string
+    SortOrder string
+    Limit     int64
+}
+
+func (q insightsQuery) String() string {
+    var cmds []string
+    if len(q.Fields) > 0 { cmds = append(cmds, "fields "+strings.Join(q.Fields, ", ")) }
+    if q.Filter != nil { cmds = append(cmds, "filter "+insightsExpr(requirePresent(q.Filter, notEqual), false)) }
+    if q.Stats != "" { cmds = append(cmds, "stats "+q.Stats) }
+    if q.SortBy != "" { cmds = append(cmds, "sort "+q.SortBy+" "+q.SortOrder) }
+    if q.Limit > 0 { cmds = append(cmds, fmt.Sprintf("limit %d", q.Limit)) }
+    return strings.Join(cmds, "\n| ")
+}
+
+func insightsExpr(e expr, nested bool) string {
+    switch n := e.(type) {
+    case allExpr:
+        return insightsJoin(n, " and ", nested)
+    case anyExpr:
+        return insightsJoin(n, " or ", nested)
+    case cmpExpr:
+        lit := quoteDouble(n.Value)
+        if n.Numeric || n.Boolean { lit = n.Value }
+        return fmt.Sprintf("%s %s %s", n.Key, n.Op, lit)
+    case presentExpr:
+        return "ispresent(" + n.Key + ")"
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func insightsJoin(children []expr, sep string, nested bool) string {
+    parts := make([]string, 0, len(children))
+    for _, c := range children { parts = append(parts, insightsExpr(c, true)) }
+    s := strings.Join(parts, sep)
+    if nested && len(parts) > 1 { return "(" + s + ")" }
+    return s
+}
@@ -0,0 +1,140 @@
+package provider
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/data"
+)
+
+// kqlFunctions maps canonical aggregate functions to KQL aggregation functions.
+var kqlFunctions = map[string]string{
+    "avg":            "avg",
+    "count":          "count",
+    "count_distinct": "dcount",
+    "max":            "max",
+    "min":            "min",
+    "stddev":         "stdev",
+    "sum":            "sum",
+}
+
+// kqlQuery is a Log Analytics KQL query over a table. Keys are read from the
+// JSON in MessageColumn, or from table columns of the same name when
+// MessageColumn is empty.
+type kqlQuery struct {
+    Table         string
+    MessageColumn string
+    Filter        expr
+    Stats         *aggregation
+}
+
+func (q kqlQuery) String() string {
+    cmds := []string{kqlName(q.Table)}
+    if q.Filter != nil { cmds = append(cmds, "where "+q.where(q.Filter)) }
+    if q.Stats != nil {
+        out := "summarize " + q.aggregate(*q.Stats)
+        if len(q.Stats.By) > 0 {
+            groups := make([]string, 0, len(q.Stats.By))
+            for _, b := range q.Stats.By {
+                // dynamic values cannot be grouped on, so parsed keys are
+                // converted and named after the key
+                if q.MessageColumn != "" { groups = append(groups, kqlName(b)+" = "+q.value(b, "tostring")); continue }
+                groups = append(groups, kqlName(b))
+            }
+            out += " by " + strings.Join(groups, ", ")
+        }
+        cmds = append(cmds, out)
+    }
+    return strings.Join(cmds, "\n| ")
+}
+
+// aggregate renders the aggregation function. count takes no argument in
+// KQL, so counting a field counts the records where it is present.
+func (q kqlQuery) aggregate(a aggregation) string {
+    switch {
+    case a.Field == "":
+        return kqlFunctions[a.Function] + "()"
+    case a.Function == "count":
+        return "countif(isnotnull(" + q.ref(a.Field) + "))"
+    }
+    return kqlFunctions[a.Function] + "(" + q.value(a.Field, kqlConversion(a)) + ")"
+}
+
+// kqlConversion returns the function converting a parsed message property
+// to the type the aggregated field holds. Untyped fields are converted as
+// the function implies.
+func kqlConversion(a aggregation) string {
+    switch {
+    case data.IsNumericKind(a.Kind):
+        return "toreal"
+    case a.Kind == data.KindTimestamp:
+        return "todatetime"
+    case a.Kind == data.KindBoolean:
+        return "tobool"
+    case (a.Kind == "" || a.Kind == data.KindAny) && contains(numericAggregates, a.Function):
+        return "toreal"
+    }
+    return "tostring"
+}
+
+// where renders e as the predicate of a where operator. A missing property
+// is null and a missing column value empty, and != matches both, so !=
+// comparisons are guarded by isnotempty.
+func (q kqlQuery) where(e expr) string {
+    return q.expr(requirePresent(e, notEqual), false)
+}
+
+func (q kqlQuery) expr(e expr, nested bool) string {
+    switch n := e.(type) {
+    case allExpr:
+        return q.join(n, " and ", nested)
+    case anyExpr:
+        return q.join(n, " or ", nested)
+    case cmpExpr:
+        op := n.Op
+        if op == opEqual { op = "==" }
+        if n.Numeric { return q.value(n.Key, "toreal") + " " + op + " " + n.Value }
+        if n.Boolean { return q.value(n.Key, "tobool") + " " + op + " " + n.Value }
+        return q.ref(n.Key) + " " + op + " " + quoteDouble(n.Value)
+    case presentExpr:
+        return "isnotempty(" + q.ref(n.Key) + ")"
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func (q kqlQuery) join(children []expr, sep string, nested bool) string {
+    parts := make([]string, 0, len(children))
+    for _, c := range children { parts = append(parts, q.expr(c, true)) }
+    s := strings.Join(parts, sep)
+    if nested && len(parts) > 1 { return "(" + s + ")" }
+    return s
+}
+
+// ref references a serialized key: a property of the parsed message, or a
+// column in the custom-table form.
+func (q kqlQuery) ref(key string) string {
+    if q.MessageColumn == "" { return kqlName(key) }
+    if isKQLIdentifier(key) { return "parse_json(" + kqlName(q.MessageColumn) + ")." + key }
+    return "parse_json(" + kqlName(q.MessageColumn) + ")[" + quoteDouble(key) + "]"
+}
+
+// value references key converted with conv, which parsed message properties
+// need before numeric comparison, aggregation or grouping.
+func (q kqlQuery) value(key, conv string) string {
+    if q.MessageColumn == "" { return kqlName(key) }
+    return conv + "(" + q.ref(key) + ")"
+}
+
+// kqlName leaves identifiers bare and brackets anything else.
+func kqlName(name string) string {
+    if isKQLIdentifier(name) { return name }
+    return "[" + quoteDouble(name) + "]"
+}
+
+func isKQLIdentifier(name string) bool {
+    if name == "" { return false }
+    for i, r := range name {
+        if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || i > 0 && r >= '0' && r <= '9') { return false }
+    }
+    return true
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+    "fmt"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// renderLoki renders e as a LogQL log query: the stream selector, a json
+// parser stage, then one label filter stage per top-level clause.
+func renderLoki(streams map[string]string, e expr) string {
+    names := make([]string, 0, len(streams))
+    for name := range streams { names = append(names, name) }
+    sort.Strings(names)
+    matchers := make([]string, 0, len(names))
+    for _, name := range names { matchers = append(matchers, name+"="+strconv.Quote(streams[name])) }
+
+    return "{" + strings.Join(matchers, ", ") + "} | " + lokiPipeline(e)
+}
+
+// lokiPipeline renders the stages that follow the stream selector. A label
+// the json stage did not extract is empty, which != matches, and a numeric
+// filter that cannot convert it keeps the line, so both are guarded by a
+// key!="" filter.
+func lokiPipeline(e expr) string {
+    e = requirePresent(e, func(c cmpExpr) bool { return c.Op == opNotEqual || c.Numeric })
+    stages := []string{"json"}
+    if all, ok := e.(allExpr); ok {
+        for _, c := range all { stages = append(stages, lokiExpr(c, false)) }
+    } else {
+        stages = append(stages, lokiExpr(e, false))
+    }
+    return strings.Join(stages, " | ")
+}
+
+func lokiExpr(e expr, nested bool) string {
+    switch n := e.(type) {
+    case allExpr:
+        return lokiJoin(n, " and ", nested)
+    case anyExpr:
+        return lokiJoin(n, " or ", nested)
+    case cmpExpr:
+        // unquoted literals make Loki compare label values as numbers
+        if n.Numeric {
+            op := n.Op
+            if op == opEqual { op = "==" }
+            return n.Key + " " + op + " " + n.Value
+        }
+        return n.Key + n.Op + strconv.Quote(n.Value)
+    case presentExpr:
+        return n.Key + `!=""`
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func lokiJoin(children []expr, sep string, nested bool) string {
+    parts := make([]string, 0, len(children))
+    for _, c := range children { parts = append(parts, lokiExpr(c, true)) }
+    s := strings.Join(parts, sep)
+    if nested && len(parts) > 1 { return "(" + s + ")" }
+    return s
+}
+
+// isLokiLabelName reports whether name is a valid Prometheus label name.
+func isLokiLabelName(name string) bool {
+    if name == "" { return false }
+    for i, r := range name {
+        if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || i > 0 && r >= '0' && r <= '9') { return false }
+    }
+    return true
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+    "fmt"
+    "strings"
+)
+
+// nrqlFunctions maps canonical aggregate functions to NRQL aggregator functions.
+var nrqlFunctions = map[string]string{
+    "avg":            "average",
+    "count":          "count",
+    "count_distinct": "uniqueCount",
+    "max":            "max",
+    "min":            "min",
+    "stddev":         "stddev",
+    "sum":            "sum",
+}
+
+// nrqlQuery is a New Relic NRQL query: an aggregation over an event type,
+// filtered by WHERE and faceted by the aggregation's group keys.
+type nrqlQuery struct {
+    From   string
+    Filter expr
+    Stats  aggregation
+}
+
+func (q nrqlQuery) String() string {
+    arg := "*"
+    if q.Stats.Field != "" { arg = nrqlName(q.Stats.Field) }
+    out := fmt.Sprintf("SELECT %s(%s) FROM %s", nrqlFunctions[q.Stats.Function], arg, nrqlName(q.From))
+    if q.Filter != nil {
+        if w := nrqlExpr(q.Filter, false); w != "" { out += " WHERE " + w }
+    }
+    if len(q.Stats.By) > 0 {
+        facets := make([]string, 0, len(q.Stats.By))
+        for _, b := range q.Stats.By { facets = append(facets, nrqlName(b)) }
+        out += " FACET " + strings.Join(facets, ", ")
+    }
+    return out
+}
+
+func nrqlExpr(e expr, nested bool) string {
+    switch n := e.(type) {
+    case allExpr:
+        return nrqlJoin(n, " AND ", nested)
+    case anyExpr:
+        return nrqlJoin(n, " OR ", nested)
+    case cmpExpr:
+        // comparisons, != included, are false for a NULL attribute, so
+        // missing keys never match without a presence guard
+        if n.Numeric || n.Boolean { return nrqlName(n.Key) + " " + n.Op + " " + n.Value }
+        return nrqlName(n.Key) + " " + n.Op + " " + nrqlString(n.Value)
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func nrqlJoin(children []expr, sep string, nested bool) string {
+    parts := make([]string, 0, len(children))
+    for _, c := range children { parts = append(parts, nrqlExpr(c, true)) }
+    s := strings.Join(parts, sep)
+    if nested && len(parts) > 1 { return "(" + s + ")" }
+    return s
+}
+
+// nrqlString wraps s in single quotes, escaping backslashes and quotes.
+func nrqlString(s string) string {
+    s = strings.ReplaceAll(s, `\`, `\\`)
+    return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// nrqlName leaves plain attribute names bare and backtick-quotes anything else.
+func nrqlName(name string) string {
+    if name == "" { return "``" }
+    for i, r := range name {
+        if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || i > 0 && (r >= '0' && r <= '9' || r == '.')) {
+            return "`" + name + "`"
+        }
+    }
+    return name
+}
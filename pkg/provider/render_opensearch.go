@@ -0,0 +1,71 @@
+package provider
+
+import (
+    "encoding/json"
+    "fmt"
+)
+
+// opensearchFields controls how serialized keys map to document fields.
+type opensearchFields struct {
+    Prefix        string // prepended to every key, e.g. "log."
+    KeywordSuffix string // appended for exact string matches, e.g. ".keyword"
+}
+
+func (f opensearchFields) name(c cmpExpr) string {
+    if c.Numeric || c.Boolean { return f.Prefix + c.Key }
+    return f.Prefix + c.Key + f.KeywordSuffix
+}
+
+// opensearchQuery renders e as an OpenSearch query DSL clause. Conjunctions
+// become bool.filter, disjunctions bool.should and != comparisons
+// bool.must_not, so no clause contributes to scoring. must_not also matches
+// documents without the field, so != comparisons are guarded by exists.
+func opensearchQuery(f opensearchFields, e expr) map[string]any {
+    return opensearchClause(f, requirePresent(e, notEqual))
+}
+
+func opensearchClause(f opensearchFields, e expr) map[string]any {
+    switch n := e.(type) {
+    case allExpr:
+        return map[string]any{"bool": map[string]any{"filter": opensearchClauses(f, n)}}
+    case anyExpr:
+        return map[string]any{"bool": map[string]any{"should": opensearchClauses(f, n), "minimum_should_match": 1}}
+    case cmpExpr:
+        var value any = n.Value
+        if n.Numeric { value = json.Number(n.Value) }
+        if n.Boolean { value = n.Value == "true" }
+        switch n.Op {
+        case opEqual:
+            return map[string]any{"term": map[string]any{f.name(n): value}}
+        case opNotEqual:
+            term := map[string]any{"term": map[string]any{f.name(n): value}}
+            return map[string]any{"bool": map[string]any{"must_not": []any{term}}}
+        }
+        bound := map[string]string{opGreater: "gt", opGreaterEqual: "gte", opLess: "lt", opLessEqual: "lte"}[n.Op]
+        return map[string]any{"range": map[string]any{f.name(n): map[string]any{bound: value}}}
+    case presentExpr:
+        return map[string]any{"exists": map[string]any{"field": f.Prefix + n.Key}}
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func opensearchClauses(f opensearchFields, children []expr) []any {
+    out := make([]any, 0, len(children))
+    for _, c := range children { out = append(out, opensearchClause(f, c)) }
+    return out
+}
+
+// opensearchMonitorQuery wraps a query in the search request of an alerting
+// monitor, restricted to the period before each run.
+func opensearchMonitorQuery(f opensearchFields, e expr, timestampField, period string) map[string]any {
+    window := map[string]any{"range": map[string]any{timestampField: map[string]any{
+        "gte":    "{{period_end}}||-" + period,
+        "lte":    "{{period_end}}",
+        "format": "epoch_millis",
+    }}}
+    filter := append(opensearchClauses(f, allExpr{}.and(requirePresent(e, notEqual))), window)
+    return map[string]any{
+        "size":  0,
+        "query": map[string]any{"bool": map[string]any{"filter": filter}},
+    }
+}
@@ -0,0 +1,79 @@
+package provider
+
+import (
+    "fmt"
+    "strings"
+)
+
+// splunkFunctions maps canonical aggregate functions to SPL stats functions.
+var splunkFunctions = map[string]string{
+    "avg":            "avg",
+    "count":          "count",
+    "count_distinct": "dc",
+    "max":            "max",
+    "min":            "min",
+    "stddev":         "stdev",
+    "sum":            "sum",
+}
+
+// splunkSearch is an SPL search: index and sourcetype terms, the filter, and
+// an optional stats command.
+type splunkSearch struct {
+    Index      string
+    Sourcetype string
+    Filter     expr
+    Stats      *aggregation
+}
+
+func (s splunkSearch) String() string {
+    var terms []string
+    if s.Index != "" { terms = append(terms, "index="+splunkValue(s.Index)) }
+    if s.Sourcetype != "" { terms = append(terms, "sourcetype="+splunkValue(s.Sourcetype)) }
+    if all, ok := s.Filter.(allExpr); ok {
+        for _, c := range all { terms = append(terms, splunkExpr(c, true)) }
+    } else if s.Filter != nil {
+        terms = append(terms, splunkExpr(s.Filter, false))
+    }
+    out := strings.Join(terms, " ")
+    if s.Stats != nil {
+        fn := splunkFunctions[s.Stats.Function]
+        if s.Stats.Field != "" { fn += "(" + s.Stats.Field + ")" }
+        out += " | stats " + fn
+        if len(s.Stats.By) > 0 { out += " by " + strings.Join(s.Stats.By, ", ") }
+    }
+    return out
+}
+
+func splunkExpr(e expr, nested bool) string {
+    switch n := e.(type) {
+    case allExpr:
+        return splunkJoin(n, " AND ", nested)
+    case anyExpr:
+        return splunkJoin(n, " OR ", nested)
+    case cmpExpr:
+        // field!=value, unlike NOT field=value, only matches events with the
+        // field, so != needs no presence guard
+        if n.Numeric { return n.Key + n.Op + n.Value }
+        return n.Key + n.Op + quoteDouble(n.Value)
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func splunkJoin(children []expr, sep string, nested bool) string {
+    parts := make([]string, 0, len(children))
+    for _, c := range children { parts = append(parts, splunkExpr(c, true)) }
+    s := strings.Join(parts, sep)
+    if nested && len(parts) > 1 { return "(" + s + ")" }
+    return s
+}
+
+// splunkValue leaves plain names bare and quotes anything else.
+func splunkValue(v string) string {
+    if v == "" { return quoteDouble(v) }
+    for _, r := range v {
+        if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == ':' || r == '.' || r == '*') {
+            return quoteDouble(v)
+        }
+    }
+    return v
+}
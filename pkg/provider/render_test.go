@@ -0,0 +1,341 @@
+package provider
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+    "testing"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/cwpattern"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testSelection compiles a selector and predicates for renderer tests.
+func testSelection(t *testing.T, in selectorInput, preds ...expr) (*MetadataClient, allExpr) {
+    t.Helper()
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+    sel, diags := c.resolveSelector(in)
+    if diags.HasError() { t.Fatalf("selector: %v", diags) }
+    for _, p := range preds { sel.Expr = sel.Expr.and(p) }
+    return c, sel.Expr
+}
+
+func mustPredicate(t *testing.T, canonical, op string, values ...string) expr {
+    t.Helper()
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+    e, err := c.predicate(canonical, op, values)
+    if err != nil { t.Fatalf("predicate: %v", err) }
+    return e
+}
+
+func TestRenderInsights(t *testing.T) {
+    c, filter := testSelection(t, selectorInput{Source: "mailer", Events: []string{"delivered"}},
+        mustPredicate(t, "http_method", "=", "GET", "POST"))
+    stats, diags := c.insightsStats(insightsStatsModel{
+        Function: types.StringValue("avg"),
+        Field:    types.StringValue("duration_ms"),
+        By:       []types.String{types.StringValue("mailer_class")},
+        Bin:      types.StringValue("5m"),
+    })
+    if diags.HasError() { t.Fatalf("stats: %v", diags) }
+
+    q := insightsQuery{Fields: []string{"@timestamp", "mailer_action"}, Filter: filter, Stats: stats, SortBy: "@timestamp", SortOrder: "desc", Limit: 20}
+    want := "fields @timestamp, mailer_action\n" +
+        "| filter evt = \"delivered\" and src = \"mailer\" and (method = \"GET\" or method = \"POST\")\n" +
+        "| stats avg(duration_ms) by mailer, bin(5m)\n" +
+        "| sort @timestamp desc\n" +
+        "| limit 20"
+    if got := q.String(); got != want { t.Errorf("got:\n%s\nwant:\n%s", got, want) }
+
+    if _, diags := c.insightsStats(insightsStatsModel{Function: types.StringValue("sum"), Field: types.StringNull(), Bin: types.StringNull()}); !diags.HasError() {
+        t.Errorf("expected error for sum without field")
+    }
+    if _, diags := c.insightsStats(insightsStatsModel{Function: types.StringValue("count"), Field: types.StringNull(), By: []types.String{types.StringValue("nope")}, Bin: types.StringNull()}); !diags.HasError() {
+        t.Errorf("expected error for unknown group key")
+    }
+    if _, diags := c.insightsStats(insightsStatsModel{Function: types.StringValue("avg"), Field: types.StringValue("mailer_class"), Bin: types.StringNull()}); !diags.HasError() {
+        t.Errorf("expected error for avg of a string field")
+    }
+    for _, bin := range []string{"5", "5min", "1h) | display @message", ""} {
+        if _, diags := c.insightsStats(insightsStatsModel{Function: types.StringValue("count"), Field: types.StringNull(), Bin: types.StringValue(bin)}); !diags.HasError() {
+            t.Errorf("expected error for bin %q", bin)
+        }
+    }
+    for _, bin := range []string{"100ms", "30s", "1mo", "2q"} {
+        if _, diags := c.insightsStats(insightsStatsModel{Function: types.StringValue("count"), Field: types.StringNull(), Bin: types.StringValue(bin)}); diags.HasError() {
+            t.Errorf("bin %q: %v", bin, diags)
+        }
+    }
+}
+
+func TestRenderDatadog(t *testing.T) {
+    _, filter := testSelection(t, selectorInput{Source: "job", Events: []string{"start", "finish"}},
+        mustPredicate(t, "queue_name", "!=", "low priority"),
+        mustPredicate(t, "duration_ms", ">=", "500"))
+    want := `(@evt:finish OR @evt:start) @src:job @queue_name:* -@queue_name:"low priority" @duration_ms:>=500`
+    if got := renderDatadog(filter); got != want { t.Errorf("got %s, want %s", got, want) }
+
+    for in, want := range map[string]string{
+        "delivered":  "delivered",
+        "a:b":        `"a:b"`,
+        `say "hi"`:   `"say \"hi\""`,
+        "OR":         `"OR"`,
+        "/users/1":   `"/users/1"`,
+    } {
+        if got := datadogValue(in); got != want { t.Errorf("%q: got %s, want %s", in, got, want) }
+    }
+}
+
+func TestRenderLoki(t *testing.T) {
+    _, filter := testSelection(t, selectorInput{Source: "job", Events: []string{"finish"}},
+        mustPredicate(t, "duration_ms", ">", "500"),
+        mustPredicate(t, "queue_name", "=", "default", "mailers"))
+    want := `{app="web", env="prod"} | json | evt="finish" | src="job" | duration_ms!="" | duration_ms > 500 | queue_name="default" or queue_name="mailers"`
+    if got := renderLoki(map[string]string{"env": "prod", "app": "web"}, filter); got != want { t.Errorf("got %s, want %s", got, want) }
+
+    for name, want := range map[string]bool{"app": true, "_x1": true, "1x": false, "a-b": false, "": false} {
+        if got := isLokiLabelName(name); got != want { t.Errorf("%q: got %v, want %v", name, got, want) }
+    }
+}
+
+func TestRenderOpenSearch(t *testing.T) {
+    _, filter := testSelection(t, selectorInput{Source: "mailer", Events: []string{"delivered"}},
+        mustPredicate(t, "duration_ms", ">", "500"),
+        mustPredicate(t, "mailer_action", "!=", "welcome"))
+    f := opensearchFields{Prefix: "log.", KeywordSuffix: ".keyword"}
+
+    got, err := json.Marshal(opensearchQuery(f, filter))
+    if err != nil { t.Fatalf("marshal: %v", err) }
+    want := `{"bool":{"filter":[` +
+        `{"term":{"log.evt.keyword":"delivered"}},` +
+        `{"term":{"log.src.keyword":"mailer"}},` +
+        `{"range":{"log.duration_ms":{"gt":500}}},` +
+        `{"exists":{"field":"log.mailer_action"}},` +
+        `{"bool":{"must_not":[{"term":{"log.mailer_action.keyword":"welcome"}}]}}]}}`
+    if string(got) != want { t.Errorf("got:\n%s\nwant:\n%s", got, want) }
+
+    _, filter = testSelection(t, selectorInput{Source: "job", Events: []string{"start", "finish"}})
+    got, err = json.Marshal(opensearchMonitorQuery(opensearchFields{}, filter, "@timestamp", "1h"))
+    if err != nil { t.Fatalf("marshal: %v", err) }
+    want = `{"query":{"bool":{"filter":[` +
+        `{"bool":{"minimum_should_match":1,"should":[{"term":{"evt":"finish"}},{"term":{"evt":"start"}}]}},` +
+        `{"term":{"src":"job"}},` +
+        `{"range":{"@timestamp":{"format":"epoch_millis","gte":"{{period_end}}||-1h","lte":"{{period_end}}"}}}]}},"size":0}`
+    if string(got) != want { t.Errorf("got:\n%s\nwant:\n%s", got, want) }
+}
+
+func TestRenderSplunk(t *testing.T) {
+    c, filter := testSelection(t, selectorInput{Source: "mailer", Events: []string{"delivered", "error"}},
+        mustPredicate(t, "duration_ms", "<=", "250"))
+    agg, diags := c.aggregation(statsModel{Function: types.StringValue("count_distinct"), Field: types.StringValue("message_id"), By: []types.String{types.StringValue("mailer_class")}}, path.Root("stats"), c.SerializedKey)
+    if diags.HasError() { t.Fatalf("stats: %v", diags) }
+
+    s := splunkSearch{Index: "main", Sourcetype: "logstruct:json", Filter: filter, Stats: &agg}
+    want := `index=main sourcetype=logstruct:json (evt="delivered" OR evt="error") src="mailer" duration_ms<=250 | stats dc(msg_id) by mailer`
+    if got := s.String(); got != want { t.Errorf("got %s, want %s", got, want) }
+
+    s = splunkSearch{Index: "web logs", Filter: filter, Stats: &aggregation{Function: "count"}}
+    want = `index="web logs" (evt="delivered" OR evt="error") src="mailer" duration_ms<=250 | stats count`
+    if got := s.String(); got != want { t.Errorf("got %s, want %s", got, want) }
+}
+
+func TestRenderGCP(t *testing.T) {
+    _, filter := testSelection(t, selectorInput{Source: "mailer", Events: []string{"delivered", "error"}},
+        mustPredicate(t, "level", "=", "warn", "error"),
+        mustPredicate(t, "duration_ms", ">", "500"))
+
+    g := gcpFilter{Payload: "jsonPayload", LevelKey: "lvl", MinSeverity: "INFO"}
+    got, err := g.render(filter)
+    if err != nil { t.Fatalf("render: %v", err) }
+    want := `(jsonPayload.evt="delivered" OR jsonPayload.evt="error") AND jsonPayload.src="mailer" AND (severity=WARNING OR severity=ERROR) AND jsonPayload.duration_ms>500 AND severity>=INFO`
+    if got != want { t.Errorf("got %s, want %s", got, want) }
+
+    // without severity mapping the level stays in the payload
+    got, err = gcpFilter{Payload: "jsonPayload"}.render(filter)
+    if err != nil { t.Fatalf("render: %v", err) }
+    if !strings.Contains(got, `jsonPayload.lvl="warn"`) { t.Errorf("expected payload level comparison, got %s", got) }
+
+    _, filter = testSelection(t, selectorInput{Source: "mailer", Events: []string{"error"}}, mustPredicate(t, "level", "=", "loud"))
+    if _, err := g.render(filter); err == nil { t.Errorf("expected error for unknown level") }
+}
+
+func TestRenderKQL(t *testing.T) {
+    c, filter := testSelection(t, selectorInput{Source: "job", Events: []string{"finish"}},
+        mustPredicate(t, "queue_name", "!=", "low", "bulk"),
+        mustPredicate(t, "duration_ms", ">=", "1000"))
+    agg, diags := c.aggregation(statsModel{Function: types.StringValue("avg"), Field: types.StringValue("duration_ms"), By: []types.String{types.StringValue("queue_name")}}, path.Root("stats"), c.SerializedKey)
+    if diags.HasError() { t.Fatalf("stats: %v", diags) }
+
+    q := kqlQuery{Table: "ContainerLogV2", MessageColumn: "LogMessage", Filter: filter, Stats: &agg}
+    want := "ContainerLogV2\n" +
+        `| where parse_json(LogMessage).evt == "finish" and parse_json(LogMessage).src == "job" and isnotempty(parse_json(LogMessage).queue_name) and parse_json(LogMessage).queue_name != "low" and parse_json(LogMessage).queue_name != "bulk" and toreal(parse_json(LogMessage).duration_ms) >= 1000` + "\n" +
+        "| summarize avg(toreal(parse_json(LogMessage).duration_ms)) by queue_name = tostring(parse_json(LogMessage).queue_name)"
+    if got := q.String(); got != want { t.Errorf("got:\n%s\nwant:\n%s", got, want) }
+
+    q = kqlQuery{Table: "LogStruct_CL", Filter: filter, Stats: &aggregation{Function: "count", By: []string{"queue_name"}}}
+    want = "LogStruct_CL\n" +
+        `| where evt == "finish" and src == "job" and isnotempty(queue_name) and queue_name != "low" and queue_name != "bulk" and duration_ms >= 1000` + "\n" +
+        "| summarize count() by queue_name"
+    if got := q.String(); got != want { t.Errorf("got:\n%s\nwant:\n%s", got, want) }
+
+    // aggregated fields are converted by kind, and count takes no argument
+    for _, tc := range []struct{ function, field, want string }{
+        {"count_distinct", "queue_name", "dcount(tostring(parse_json(LogMessage).queue_name))"},
+        {"max", "timestamp", "max(todatetime(parse_json(LogMessage).ts))"},
+        {"sum", "duration_ms", "sum(toreal(parse_json(LogMessage).duration_ms))"},
+        {"count", "queue_name", "countif(isnotnull(parse_json(LogMessage).queue_name))"},
+    } {
+        agg, diags := c.aggregation(statsModel{Function: types.StringValue(tc.function), Field: types.StringValue(tc.field)}, path.Root("stats"), c.SerializedKey)
+        if diags.HasError() { t.Fatalf("%s(%s): %v", tc.function, tc.field, diags) }
+        q := kqlQuery{Table: "T", MessageColumn: "LogMessage", Stats: &agg}
+        if got := q.String(); got != "T\n| summarize "+tc.want { t.Errorf("%s(%s): got %s, want %s", tc.function, tc.field, got, tc.want) }
+    }
+
+    _, filter = testSelection(t, selectorInput{Source: "job", Events: []string{"start", "finish"}})
+    q = kqlQuery{Table: "my table", Filter: filter}
+    want = "[\"my table\"]\n| where (evt == \"finish\" or evt == \"start\") and src == \"job\""
+    if got := q.String(); got != want { t.Errorf("got:\n%s\nwant:\n%s", got, want) }
+}
+
+func TestRenderNRQL(t *testing.T) {
+    c, filter := testSelection(t, selectorInput{Source: "mailer", Events: []string{"delivered"}})
+    agg, diags := c.aggregation(statsModel{Function: types.StringNull(), Field: types.StringNull(), By: []types.String{types.StringValue("mailer_action")}}, path.Root("stats"), c.SerializedKey)
+    if diags.HasError() { t.Fatalf("stats: %v", diags) }
+
+    q := nrqlQuery{From: "Log", Filter: filter, Stats: agg}
+    want := "SELECT count(*) FROM Log WHERE evt = 'delivered' AND src = 'mailer' FACET mailer_action"
+    if got := q.String(); got != want { t.Errorf("got %s, want %s", got, want) }
+
+    _, filter = testSelection(t, selectorInput{Source: "job", Events: []string{"start", "finish"}},
+        mustPredicate(t, "queue_name", "=", "it's"),
+        mustPredicate(t, "duration_ms", ">", "100"))
+    q = nrqlQuery{From: "Log", Filter: filter, Stats: aggregation{Function: "avg", Field: "duration_ms"}}
+    want = `SELECT average(duration_ms) FROM Log WHERE (evt = 'finish' OR evt = 'start') AND src = 'job' AND queue_name = 'it\'s' AND duration_ms > 100`
+    if got := q.String(); got != want { t.Errorf("got %s, want %s", got, want) }
+
+    if _, diags := c.aggregation(statsModel{Function: types.StringNull(), Field: types.StringNull(), By: []types.String{types.StringValue("mailer_actoin")}}, path.Root("stats"), c.SerializedKey); !diags.HasError() {
+        t.Errorf("expected error for misspelled facet key")
+    }
+}
+
+func TestRenderVRL(t *testing.T) {
+    _, filter := testSelection(t, selectorInput{Source: "job", Events: []string{"finish"}})
+    if got, want := renderVRL(filter), `.evt == "finish" && .src == "job"`; got != want { t.Errorf("got %s, want %s", got, want) }
+
+    _, filter = testSelection(t, selectorInput{Source: "job", Events: []string{"start", "finish"}},
+        mustPredicate(t, "queue_name", "!=", "low"),
+        mustPredicate(t, "duration_ms", ">", "1000"))
+    want := `(.evt == "finish" || .evt == "start") && .src == "job" && exists(.queue_name) && .queue_name != "low" && ((is_integer(.duration_ms) || is_float(.duration_ms)) && (to_float(.duration_ms) ?? 0) > 1000)`
+    if got := renderVRL(filter); got != want { t.Errorf("got %s, want %s", got, want) }
+
+    // a missing or non-numeric field never matches a numeric comparison
+    _, filter = testSelection(t, selectorInput{Source: "job", Events: []string{"finish"}}, mustPredicate(t, "duration_ms", "<", "5"))
+    want = `.evt == "finish" && .src == "job" && ((is_integer(.duration_ms) || is_float(.duration_ms)) && (to_float(.duration_ms) ?? 0) < 5)`
+    if got := renderVRL(filter); got != want { t.Errorf("got %s, want %s", got, want) }
+}
+
+func TestRenderFluentBit(t *testing.T) {
+    _, filter := testSelection(t, selectorInput{Source: "job", Events: []string{"start", "finish"}},
+        mustPredicate(t, "queue_name", "!=", "low", "bulk"),
+        mustPredicate(t, "job_class", "=", "Mailer.deliver"))
+    g := fluentBitGrep{Match: "app.*"}
+    rules, err := g.rules(filter)
+    if err != nil { t.Fatalf("rules: %v", err) }
+    g.Rules = rules
+    want := "[FILTER]\n" +
+        "    Name    grep\n" +
+        "    Match   app.*\n" +
+        "    Regex   evt ^(finish|start)$\n" +
+        "    Regex   src ^job$\n" +
+        "    Regex   queue_name ^.*$\n" +
+        "    Exclude queue_name ^low$\n" +
+        "    Exclude queue_name ^bulk$\n" +
+        "    Regex   job_class ^Mailer\\.deliver$\n"
+    if got := g.String(); got != want { t.Errorf("got:\n%s\nwant:\n%s", got, want) }
+
+    rules, err = fluentBitGrep{RecordKey: "log"}.rules(filter)
+    if err != nil { t.Fatalf("rules: %v", err) }
+    if got := rules[1].String(); got != "$log['src'] ^job$" { t.Errorf("got %s", got) }
+
+    _, filter = testSelection(t, selectorInput{Source: "job", Events: []string{"finish"}}, mustPredicate(t, "duration_ms", ">", "1000"))
+    if _, err := g.rules(filter); err == nil { t.Errorf("expected error for numeric comparison") }
+}
+
+// TestMissingKeyRule renders a negated comparison in every dialect: each
+// must leave out logs without the key, as CloudWatch does.
+func TestMissingKeyRule(t *testing.T) {
+    e := allExpr{cmpExpr{Key: "src", Op: opEqual, Value: "job"}, negate(cmpExpr{Key: "queue_name", Op: opEqual, Value: "low"})}
+
+    p, err := cwpattern.Parse(renderCloudWatch(e))
+    if err != nil { t.Fatalf("parse: %v", err) }
+    for event, want := range map[string]bool{`{"src": "job"}`: false, `{"src": "job", "queue_name": "high"}`: true, `{"src": "job", "queue_name": "low"}`: false} {
+        if got := p.Match(event); got != want { t.Errorf("%s against %s: got %v, want %v", p.Src, event, got, want) }
+    }
+
+    osq, err := json.Marshal(opensearchQuery(opensearchFields{}, e))
+    if err != nil { t.Fatalf("marshal: %v", err) }
+    gcp, err := gcpFilter{Payload: "jsonPayload"}.render(e)
+    if err != nil { t.Fatalf("gcp: %v", err) }
+    rules, err := fluentBitGrep{}.rules(e)
+    if err != nil { t.Fatalf("rules: %v", err) }
+    for _, tc := range []struct{ dialect, got, want string }{
+        // these dialects never match a comparison on a missing field
+        {"cloudwatch", renderCloudWatch(e), `{ $.src = "job" && $.queue_name != "low" }`},
+        {"splunk", splunkExpr(e, false), `src="job" AND queue_name!="low"`},
+        {"nrql", nrqlExpr(e, false), `src = 'job' AND queue_name != 'low'`},
+        // the rest need a presence test
+        {"insights", insightsQuery{Filter: e}.String(), `filter src = "job" and ispresent(queue_name) and queue_name != "low"`},
+        {"datadog", renderDatadog(e), `@src:job @queue_name:* -@queue_name:low`},
+        {"loki", lokiPipeline(e), `json | src="job" | queue_name!="" | queue_name!="low"`},
+        {"opensearch", string(osq), `{"bool":{"filter":[{"term":{"src":"job"}},{"exists":{"field":"queue_name"}},{"bool":{"must_not":[{"term":{"queue_name":"low"}}]}}]}}`},
+        {"gcp", gcp, `jsonPayload.src="job" AND jsonPayload.queue_name:* AND jsonPayload.queue_name!="low"`},
+        {"kql", kqlQuery{}.where(e), `src == "job" and isnotempty(queue_name) and queue_name != "low"`},
+        {"vrl", renderVRL(e), `.src == "job" && exists(.queue_name) && .queue_name != "low"`},
+        {"fluentbit", fmt.Sprint(rules), `[src ^job$ queue_name ^.*$ queue_name ^low$]`},
+    } {
+        if tc.got != tc.want { t.Errorf("%s: got %s, want %s", tc.dialect, tc.got, tc.want) }
+    }
+    if len(rules) == 3 && (rules[1].Type != "Regex" || rules[2].Type != "Exclude") { t.Errorf("fluentbit: got %+v", rules) }
+
+    // the guard also applies within alternatives, once per key in a conjunction
+    alts := negate(allExpr{cmpExpr{Key: "queue_name", Op: opEqual, Value: "low"}, cmpExpr{Key: "priority", Op: opEqual, Value: "high"}})
+    if got, want := renderDatadog(alts), `(@queue_name:* AND -@queue_name:low) OR (@priority:* AND -@priority:high)`; got != want { t.Errorf("got %s, want %s", got, want) }
+    both := allExpr{cmpExpr{Key: "evt", Op: opNotEqual, Value: "exist"}, cmpExpr{Key: "evt", Op: opNotEqual, Value: "url"}}
+    if got, want := renderVRL(both), `exists(.evt) && .evt != "exist" && .evt != "url"`; got != want { t.Errorf("got %s, want %s", got, want) }
+}
+
+// TestRenderBoolean renders a boolean predicate in every dialect as a
+// typed literal rather than the string "true".
+func TestRenderBoolean(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+    e, err := c.predicate("exist", "=", []string{"true"})
+    if err != nil { t.Fatalf("predicate: %v", err) }
+    osq, err := json.Marshal(opensearchQuery(opensearchFields{KeywordSuffix: ".keyword"}, e))
+    if err != nil { t.Fatalf("marshal: %v", err) }
+    gcp, err := gcpFilter{Payload: "jsonPayload"}.render(e)
+    if err != nil { t.Fatalf("gcp: %v", err) }
+    for _, tc := range []struct{ dialect, got, want string }{
+        {"cloudwatch", renderCloudWatch(e), `{ $.exist IS TRUE }`},
+        {"insights", insightsExpr(e, false), `exist = true`},
+        {"datadog", renderDatadog(e), `@exist:true`},
+        {"loki", lokiPipeline(e), `json | exist="true"`},
+        {"opensearch", string(osq), `{"term":{"exist":true}}`},
+        {"splunk", splunkExpr(e, false), `exist="true"`},
+        {"gcp", gcp, `jsonPayload.exist=true`},
+        {"kql", kqlQuery{MessageColumn: "Message"}.where(e), `tobool(parse_json(Message).exist) == true`},
+        {"nrql", nrqlExpr(e, false), `exist = true`},
+        {"vrl", renderVRL(e), `.exist == true`},
+    } {
+        if tc.got != tc.want { t.Errorf("%s: got %s, want %s", tc.dialect, tc.got, tc.want) }
+    }
+    if _, err := (fluentBitGrep{}).rules(e); err == nil { t.Errorf("fluentbit: expected an error for a boolean") }
+
+    ne, err := c.predicate("exist", "!=", []string{"true"})
+    if err != nil { t.Fatalf("predicate: %v", err) }
+    if got, want := renderVRL(ne), `exists(.exist) && .exist != true`; got != want { t.Errorf("vrl: got %s, want %s", got, want) }
+    if got, want := renderCloudWatch(negate(ne)), `{ $.exist IS TRUE }`; got != want { t.Errorf("negated: got %s, want %s", got, want) }
+}
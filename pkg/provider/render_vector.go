@@ -0,0 +1,58 @@
+package provider
+
+import (
+    "fmt"
+    "strings"
+)
+
+// renderVRL renders e as a Vector Remap Language condition, for the
+// condition of a filter or route transform. A missing field is null, which
+// != matches, so string != comparisons are guarded by exists.
+func renderVRL(e expr) string {
+    return vrlExpr(requirePresent(e, func(c cmpExpr) bool { return c.Op == opNotEqual && !c.Numeric }), false)
+}
+
+func vrlExpr(e expr, nested bool) string {
+    switch n := e.(type) {
+    case allExpr:
+        return vrlJoin(n, " && ", nested)
+    case anyExpr:
+        return vrlJoin(n, " || ", nested)
+    case cmpExpr:
+        op := n.Op
+        if op == opEqual { op = "==" }
+        // fields are untyped, so numeric comparisons need an explicit
+        // conversion to be infallible; the type guard keeps missing and
+        // non-numeric fields from matching, as in the other dialects
+        if n.Numeric {
+            p := vrlPath(n.Key)
+            s := fmt.Sprintf("(is_integer(%s) || is_float(%s)) && (to_float(%s) ?? 0) %s %s", p, p, p, op, n.Value)
+            if nested { return "(" + s + ")" }
+            return s
+        }
+        if n.Boolean { return vrlPath(n.Key) + " " + op + " " + n.Value }
+        return vrlPath(n.Key) + " " + op + " " + quoteDouble(n.Value)
+    case presentExpr:
+        return "exists(" + vrlPath(n.Key) + ")"
+    }
+    panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func vrlJoin(children []expr, sep string, nested bool) string {
+    parts := make([]string, 0, len(children))
+    for _, c := range children { parts = append(parts, vrlExpr(c, true)) }
+    s := strings.Join(parts, sep)
+    if nested && len(parts) > 1 { return "(" + s + ")" }
+    return s
+}
+
+// vrlPath returns the event path of a top-level key, quoting segments that
+// are not plain identifiers.
+func vrlPath(key string) string {
+    for i, r := range key {
+        if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || i > 0 && r >= '0' && r <= '9') {
+            return "." + quoteDouble(key)
+        }
+    }
+    return "." + key
+}
@@ -0,0 +1,247 @@
+package provider
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// structSelector validates event against the struct's allowed events and
+// returns the clauses selecting it: the event, plus the fixed source when the
+// struct has one.
+func (c *MetadataClient) structSelector(structName, event string) (allExpr, error) {
+    allowed, _, err := c.AllowedEventsForStruct(structName)
+    if err != nil { return nil, err }
+    if !contains(allowed, event) { return nil, fmt.Errorf("event %s is not allowed for struct %s", event, structName) }
+
+    evtKey, ok := c.Keys["event"]
+    if !ok { return nil, fmt.Errorf("'event' key missing from catalog") }
+    parts := allExpr{cmpExpr{Key: evtKey, Op: opEqual, Value: event}}
+    srcVal, fixed, err := c.FixedSourceForStruct(structName)
+    if err != nil { return nil, err }
+    if fixed {
+        srcKey, ok := c.Keys["source"]
+        if !ok { return nil, fmt.Errorf("'source' key missing from catalog") }
+        parts = append(parts, cmpExpr{Key: srcKey, Op: opEqual, Value: srcVal})
+    }
+    return parts, nil
+}
+
+// selectorInput holds the source, struct and event attributes shared by the
+// data sources that select LogStruct logs by source or struct.
+type selectorInput struct {
+    Source string // empty selects by Struct alone
+    Struct string // struct pin when Source is set
+    Events []string // matched with OR; empty matches every event of the source
+    // EventsPath is the attribute the events came from, for diagnostics.
+    EventsPath    path.Path
+    ExcludeEvents []string
+}
+
+// selection is a resolved selectorInput.
+type selection struct {
+    // Struct is the resolved struct, or empty when the events span several structs.
+    Struct string
+    Expr   allExpr
+}
+
+// resolveSelector validates a source or struct and its events against the
+// catalog, resolves the struct they belong to and compiles the selecting
+// clauses.
+func (c *MetadataClient) resolveSelector(in selectorInput) (selection, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    var sel selection
+
+    var scope []string
+    switch {
+    case in.Source != "":
+        candidates := c.StructsForSource(in.Source)
+        if len(candidates) == 0 {
+            diags.AddAttributeError(path.Root("source"), "Unknown source", "No structs found with fixed source = "+in.Source)
+            return sel, diags
+        }
+        if in.Struct != "" && !contains(candidates, in.Struct) {
+            diags.AddAttributeError(path.Root("struct"), "Invalid struct",
+                fmt.Sprintf("struct %s does not have source %s; structs for this source: %s", in.Struct, in.Source, strings.Join(candidates, ", ")))
+            return sel, diags
+        }
+        scope = candidates
+        if in.Struct != "" { scope = []string{in.Struct} }
+    case in.Struct != "":
+        if _, ok := c.Structs[in.Struct]; !ok {
+            diags.AddAttributeError(path.Root("struct"), "Unknown struct", "No struct named "+in.Struct+" in the catalog")
+            return sel, diags
+        }
+        scope = []string{in.Struct}
+    default:
+        diags.AddAttributeError(path.Root("source"), "Invalid input", "one of source or struct is required")
+        return sel, diags
+    }
+    target := "source " + in.Source
+    if in.Struct != "" { target = "struct " + in.Struct }
+
+    // every event must be allowed by some struct in scope; track the structs
+    // that allow all of them
+    allowedBy := map[string][]string{}
+    for _, sname := range scope {
+        allowed, _, err := c.AllowedEventsForStruct(sname)
+        if err != nil { diags.AddError("Lookup error", err.Error()); return sel, diags }
+        allowedBy[sname] = allowed
+    }
+    covering := append([]string(nil), scope...)
+    for _, ev := range in.Events {
+        found := false
+        var still []string
+        for _, sname := range scope { if contains(allowedBy[sname], ev) { found = true } }
+        for _, sname := range covering { if contains(allowedBy[sname], ev) { still = append(still, sname) } }
+        covering = still
+        if !found {
+            diags.AddAttributeError(in.EventsPath, "Invalid event", "event "+ev+" is not allowed for "+target)
+        }
+    }
+    // exclusions must be events the source can emit at all
+    union := map[string]bool{}
+    for _, sname := range scope { for _, ev := range allowedBy[sname] { union[ev] = true } }
+    for _, ev := range in.ExcludeEvents {
+        if !union[ev] {
+            diags.AddAttributeError(path.Root("exclude_events"), "Invalid event", "event "+ev+" is not allowed for "+target)
+        }
+    }
+    if len(in.Events) > 0 && len(in.ExcludeEvents) > 0 {
+        diags.AddAttributeError(path.Root("exclude_events"), "Conflicting attributes", "exclude_events can only be used without event or events")
+    }
+    if diags.HasError() { return sel, diags }
+
+    if len(in.Events) == 0 {
+        // source-wide: only a lone struct is unambiguous
+        if len(scope) == 1 { sel.Struct = scope[0] }
+    } else if len(covering) > 0 {
+        sel.Struct = covering[0]
+        if len(covering) > 1 {
+            diags.AddAttributeWarning(path.Root("struct"), "Ambiguous struct",
+                fmt.Sprintf("source %s with event %s matches structs %s; using %s. Set struct to choose one explicitly.",
+                    in.Source, strings.Join(in.Events, ", "), strings.Join(covering, ", "), sel.Struct))
+        }
+    }
+
+    src := in.Source
+    if src == "" {
+        fixed, ok, err := c.FixedSourceForStruct(in.Struct)
+        if err != nil { diags.AddError("Lookup error", err.Error()); return sel, diags }
+        if ok { src = fixed }
+    }
+    evtKey, ok := c.Keys["event"]
+    if !ok { diags.AddError("Missing key", "'event' key missing from catalog"); return sel, diags }
+    srcKey, ok := c.Keys["source"]
+    if !ok { diags.AddError("Missing key", "'source' key missing from catalog"); return sel, diags }
+    if len(in.Events) > 0 { sel.Expr = sel.Expr.and(eventsExpr(evtKey, in.Events)) }
+    if src != "" { sel.Expr = append(sel.Expr, cmpExpr{Key: srcKey, Op: opEqual, Value: src}) }
+    excluded := append([]string(nil), in.ExcludeEvents...)
+    sort.Strings(excluded)
+    for _, ev := range excluded { sel.Expr = append(sel.Expr, cmpExpr{Key: evtKey, Op: opNotEqual, Value: ev}) }
+    // a struct without a fixed source is only told apart by its events; an
+    // empty selector would render as a pattern matching nothing or everything
+    if len(sel.Expr) == 0 {
+        allowed, _, _ := c.AllowedEventsForStruct(in.Struct)
+        diags.AddAttributeError(path.Root("struct"), "Invalid input",
+            fmt.Sprintf("struct %s has no fixed source, so it cannot be selected by struct alone; set event or events (one of: %s)", in.Struct, strings.Join(allowed, ", ")))
+    }
+    return sel, diags
+}
+
+// selectorConfig holds the selector attributes as read from configuration.
+type selectorConfig struct {
+    Source        types.String
+    Struct        types.String
+    Event         types.String
+    Events        []types.String
+    ExcludeEvents []types.String
+    Predicates    []predicateModel
+}
+
+// selectorConfigOf collects the selector attributes of a data source model.
+// Every selecting data source declares them as its own model fields, since
+// tfsdk models cannot embed a shared struct.
+func selectorConfigOf(source, structName, event types.String, events, excludeEvents []types.String, predicates []predicateModel) selectorConfig {
+    return selectorConfig{Source: source, Struct: structName, Event: event, Events: events, ExcludeEvents: excludeEvents, Predicates: predicates}
+}
+
+// selectorAttributes adds the selector attributes to attrs and returns it.
+func selectorAttributes(attrs map[string]schema.Attribute) map[string]schema.Attribute {
+    attrs["source"] = schema.StringAttribute{Optional: true, Description: "Canonical source value (e.g., mailer, job, rails, storage); one of source or struct is required"}
+    attrs["struct"] = schema.StringAttribute{Optional: true, Computed: true, Description: "LogStruct struct to select by, or to pin when several share the source; set to the resolved struct"}
+    attrs["event"] = schema.StringAttribute{Optional: true, Description: "Serialized event value (e.g., delivered, finish, database)"}
+    attrs["events"] = schema.SetAttribute{Optional: true, ElementType: types.StringType, Description: "Several serialized event values, matched with OR; alternative to event"}
+    attrs["exclude_events"] = schema.SetAttribute{Optional: true, ElementType: types.StringType, Description: "Events to exclude when neither event nor events is set"}
+    return attrs
+}
+
+// compileSelector resolves the selector attributes and ANDs the predicates
+// onto the selecting clauses. The resolved struct is null when the events
+// span several structs.
+func (c *MetadataClient) compileSelector(cfg selectorConfig) (selection, types.String, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    events, eventsPath, d := eventValues(cfg.Event, cfg.Events)
+    diags.Append(d...)
+    if diags.HasError() { return selection{}, types.StringNull(), diags }
+
+    sel, d := c.resolveSelector(selectorInput{
+        Source:        cfg.Source.ValueString(),
+        Struct:        cfg.Struct.ValueString(),
+        Events:        events,
+        EventsPath:    eventsPath,
+        ExcludeEvents: stringValues(cfg.ExcludeEvents),
+    })
+    diags.Append(d...)
+    if diags.HasError() { return sel, types.StringNull(), diags }
+
+    preds, d := c.predicateExprs(cfg.Predicates, path.Root("predicates"))
+    diags.Append(d...)
+    for _, p := range preds { sel.Expr = sel.Expr.and(p) }
+
+    resolved := types.StringNull()
+    if sel.Struct != "" { resolved = types.StringValue(sel.Struct) }
+    return sel, resolved, diags
+}
+
+// eventsExpr matches any of events, in sorted order.
+func eventsExpr(evtKey string, events []string) expr {
+    sorted := append([]string(nil), events...)
+    sort.Strings(sorted)
+    var alts anyExpr
+    for _, ev := range sorted { alts = append(alts, cmpExpr{Key: evtKey, Op: opEqual, Value: ev}) }
+    if len(alts) == 1 { return alts[0] }
+    return alts
+}
+
+// eventValues reads the mutually exclusive event and events attributes,
+// returning the events and the path they came from. Neither being set yields
+// no events.
+func eventValues(event types.String, events []types.String) ([]string, path.Path, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    if !event.IsNull() && len(events) > 0 {
+        diags.AddAttributeError(path.Root("events"), "Conflicting attributes", "set either event or events, not both")
+        return nil, path.Root("event"), diags
+    }
+    if !event.IsNull() {
+        if event.ValueString() == "" { diags.AddAttributeError(path.Root("event"), "Invalid event", "event cannot be empty") }
+        return []string{event.ValueString()}, path.Root("event"), diags
+    }
+    return stringValues(events), path.Root("events"), diags
+}
+
+func stringValues(values []types.String) []string {
+    var out []string
+    for _, v := range values { out = append(out, v.ValueString()) }
+    return out
+}
+
+func contains(list []string, s string) bool {
+    for _, v := range list { if v == s { return true } }
+    return false
+}
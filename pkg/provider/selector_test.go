@@ -0,0 +1,143 @@
+package provider
+
+import (
+    "encoding/json"
+    "strings"
+    "testing"
+
+    "github.com/DocSpring/terraform-provider-logstruct/pkg/cwpattern"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestStructSelector(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+
+    parts, err := c.structSelector("ActionMailer", "delivered")
+    if err != nil { t.Fatalf("ActionMailer: %v", err) }
+    if got, want := renderCloudWatch(parts), `{ $.evt = "delivered" && $.src = "mailer" }`; got != want {
+        t.Errorf("got %s, want %s", got, want)
+    }
+
+    // Error has no fixed source, so only the event is matched
+    parts, err = c.structSelector("Error", "error")
+    if err != nil { t.Fatalf("Error: %v", err) }
+    if got, want := renderCloudWatch(parts), `{ $.evt = "error" }`; got != want {
+        t.Errorf("got %s, want %s", got, want)
+    }
+
+    if _, err := c.structSelector("ActionMailer", "finish"); err == nil { t.Errorf("expected invalid event error") }
+    if _, err := c.structSelector("Nope", "finish"); err == nil { t.Errorf("expected unknown struct error") }
+}
+
+func TestResolveSelector(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+    events := path.Root("events")
+
+    // ambiguous: both ActiveJob and GoodJob allow start and finish
+    sel, diags := c.resolveSelector(selectorInput{Source: "job", Events: []string{"start", "finish"}, EventsPath: events})
+    if diags.HasError() { t.Fatalf("job: %v", diags) }
+    if diags.WarningsCount() != 1 { t.Errorf("expected ambiguity warning, got %v", diags) }
+    if sel.Struct != "ActiveJob" { t.Errorf("struct: got %s", sel.Struct) }
+    if got, want := renderCloudWatch(sel.Expr), `{ ($.evt = "finish" || $.evt = "start") && $.src = "job" }`; got != want {
+        t.Errorf("got %s, want %s", got, want)
+    }
+
+    // only GoodJob allows error
+    sel, diags = c.resolveSelector(selectorInput{Source: "job", Events: []string{"start", "error"}, EventsPath: events})
+    if diags.HasError() || diags.WarningsCount() != 0 { t.Fatalf("job error: %v", diags) }
+    if sel.Struct != "GoodJob" { t.Errorf("struct: got %s", sel.Struct) }
+
+    // events spanning several structs resolve to no single struct
+    sel, diags = c.resolveSelector(selectorInput{Source: "app", Events: []string{"log", "database"}, EventsPath: events})
+    if diags.HasError() { t.Fatalf("app: %v", diags) }
+    if sel.Struct != "" { t.Errorf("expected no struct, got %s", sel.Struct) }
+
+    // pinning
+    sel, diags = c.resolveSelector(selectorInput{Source: "job", Struct: "GoodJob", Events: []string{"enqueue"}, EventsPath: events})
+    if diags.HasError() || diags.WarningsCount() != 0 || sel.Struct != "GoodJob" { t.Errorf("pinned GoodJob: %s %v", sel.Struct, diags) }
+    if _, diags = c.resolveSelector(selectorInput{Source: "job", Struct: "ActiveJob", Events: []string{"error"}, EventsPath: events}); !diags.HasError() {
+        t.Errorf("expected error for event not allowed by pinned struct")
+    }
+    if _, diags = c.resolveSelector(selectorInput{Source: "job", Struct: "Puma", Events: []string{"start"}, EventsPath: events}); !diags.HasError() {
+        t.Errorf("expected error for struct with another source")
+    }
+    if _, diags = c.resolveSelector(selectorInput{Source: "job", Events: []string{"start", "nope"}, EventsPath: events}); !diags.HasError() {
+        t.Errorf("expected error for unknown event")
+    }
+}
+
+func TestResolveSelector_SourceWide(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+
+    sel, diags := c.resolveSelector(selectorInput{Source: "storage", ExcludeEvents: []string{"url", "exist"}, EventsPath: path.Root("event")})
+    if diags.HasError() { t.Fatalf("storage: %v", diags) }
+    if sel.Struct != "ActiveStorage" { t.Errorf("struct: got %s", sel.Struct) }
+    if got, want := renderCloudWatch(sel.Expr), `{ $.src = "storage" && $.evt != "exist" && $.evt != "url" }`; got != want {
+        t.Errorf("got %s, want %s", got, want)
+    }
+
+    // several structs share app, so none is reported
+    sel, diags = c.resolveSelector(selectorInput{Source: "app", EventsPath: path.Root("event")})
+    if diags.HasError() || diags.WarningsCount() != 0 { t.Fatalf("app: %v", diags) }
+    if sel.Struct != "" { t.Errorf("expected no struct, got %s", sel.Struct) }
+
+    if _, diags = c.resolveSelector(selectorInput{Source: "storage", ExcludeEvents: []string{"delivered"}}); !diags.HasError() {
+        t.Errorf("expected error for exclusion the source cannot emit")
+    }
+    if _, diags = c.resolveSelector(selectorInput{Source: "storage", Events: []string{"upload"}, ExcludeEvents: []string{"url"}}); !diags.HasError() {
+        t.Errorf("expected error combining events and exclusions")
+    }
+}
+
+func TestResolveSelector_ByStruct(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+
+    sel, diags := c.resolveSelector(selectorInput{Struct: "ActionMailer", Events: []string{"delivered"}, EventsPath: path.Root("event")})
+    if diags.HasError() { t.Fatalf("ActionMailer: %v", diags) }
+    if got, want := renderCloudWatch(sel.Expr), `{ $.evt = "delivered" && $.src = "mailer" }`; got != want {
+        t.Errorf("got %s, want %s", got, want)
+    }
+
+    sel, diags = c.resolveSelector(selectorInput{Struct: "Error", Events: []string{"error"}, EventsPath: path.Root("event")})
+    if diags.HasError() { t.Fatalf("Error: %v", diags) }
+    if got, want := renderCloudWatch(sel.Expr), `{ $.evt = "error" }`; got != want {
+        t.Errorf("got %s, want %s", got, want)
+    }
+
+    // a sourceless struct needs events, or the selector is empty
+    if _, diags = c.resolveSelector(selectorInput{Struct: "Error"}); !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), "no fixed source") {
+        t.Errorf("expected sourceless struct error, got %v", diags)
+    }
+    if _, diags = c.resolveSelector(selectorInput{Struct: "Nope"}); !diags.HasError() { t.Errorf("expected unknown struct error") }
+    if _, diags = c.resolveSelector(selectorInput{}); !diags.HasError() { t.Errorf("expected error without source or struct") }
+}
+
+// TestSelectorPatternsMatch proves with the pattern evaluator that every
+// compiled struct and event pattern matches the log line it selects and no
+// other event of the struct.
+func TestSelectorPatternsMatch(t *testing.T) {
+    c, err := NewMetadataClient()
+    if err != nil { t.Fatalf("client: %v", err) }
+    for name, sc := range c.Structs {
+        for _, ev := range sc.AllowedEvents {
+            sel, diags := c.resolveSelector(selectorInput{Struct: name, Events: []string{ev}, EventsPath: path.Root("events")})
+            if diags.HasError() { t.Fatalf("%s/%s: %v", name, ev, diags) }
+            pattern := renderCloudWatch(sel.Expr)
+            p, err := cwpattern.Parse(pattern)
+            if err != nil { t.Fatalf("%s/%s: parse %s: %v", name, ev, pattern, err) }
+
+            line := map[string]any{c.Keys["event"]: ev, c.Keys["timestamp"]: "2025-01-01T00:00:00Z"}
+            if sc.FixedSource != nil { line[c.Keys["source"]] = *sc.FixedSource }
+            b, _ := json.Marshal(line)
+            if !p.Match(string(b)) { t.Errorf("%s does not match %s", pattern, b) }
+
+            line[c.Keys["event"]] = ev + "_other"
+            b, _ = json.Marshal(line)
+            if p.Match(string(b)) { t.Errorf("%s matches %s", pattern, b) }
+        }
+    }
+}